@@ -3,12 +3,14 @@ package ast
 import (
 	"Mon/token"
 	"bytes"
+	"math/big"
 	"strings"
 )
 
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position
 }
 
 type Statement interface {
@@ -36,6 +38,14 @@ type LetStatement struct {
 	Value Expression
 }
 
+// ConstStatement binds Name like a LetStatement, but the binding can not be
+// reassigned or redeclared afterwards.
+type ConstStatement struct {
+	Token token.Token // the CONST token
+	Name  *Identifier
+	Value Expression
+}
+
 type Identifier struct {
 	Token token.Token
 	Value string
@@ -49,6 +59,12 @@ type ReturnStatement struct {
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
+	Big   *big.Int // set instead of Value when the literal overflows int64
+}
+
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
 }
 
 type PrefixExpression struct {
@@ -64,6 +80,15 @@ type InfixExpression struct {
 	Right    Expression
 }
 
+// AssignExpression stores Value into an existing binding. Operator is "="
+// or a compound operator such as "+=".
+type AssignExpression struct {
+	Token    token.Token // the assignment operator token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -76,15 +101,60 @@ type IfExpression struct {
 	Alternative *BlockStatement
 }
 
+// MatchExpression evaluates the Body of the first arm whose Pattern matches
+// Subject.
+type MatchExpression struct {
+	Token   token.Token // the MATCH token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+type MatchArm struct {
+	Pattern Expression
+	Body    Expression
+}
+
+// ArrayPattern matches an array with one element per pattern in Elements,
+// or at least that many if it has a Rest, which is bound to the others.
+type ArrayPattern struct {
+	Token    token.Token // the [ token
+	Elements []Expression
+	Rest     *Identifier
+}
+
+// HashPattern matches a hash that has all of Keys, with each value matching
+// the pattern of the same index in Values. Other keys are ignored.
+type HashPattern struct {
+	Token  token.Token // the { token
+	Keys   []Expression
+	Values []Expression
+}
+
 type BlockStatement struct {
 	Token      token.Token // the { token
 	Statements []Statement
 }
 
+type WhileStatement struct {
+	Token     token.Token // the WHILE token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+type ForStatement struct {
+	Token    token.Token // the FOR token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
 type FunctionLiteral struct {
 	Token      token.Token
 	Parameters []*Identifier
+	Defaults   []Expression // default value of each parameter, nil if required
+	Rest       *Identifier  // collects extra arguments, nil if not variadic
 	Body       *BlockStatement
+	Name       string // name of the let binding, empty if anonymous
 }
 
 type CallExpression struct {
@@ -98,6 +168,15 @@ type StringLiteral struct {
 	Value string
 }
 
+// InterpolatedString is a string literal with ${...} expressions in it.
+// Strings holds the text around them, so it has one more element than
+// Expressions.
+type InterpolatedString struct {
+	Token       token.Token // the TEMPLATE_HEAD token
+	Strings     []string
+	Expressions []Expression
+}
+
 type ArrayLiteral struct {
 	Token    token.Token
 	Elements []Expression
@@ -109,6 +188,17 @@ type IndexExpression struct {
 	Index Expression
 }
 
+// SliceExpression is left[Start:End:Step], the elements of an array or the
+// characters of a string from Start up to but not including End, taking
+// every Step-th one. Any of Start, End and Step may be nil when omitted.
+type SliceExpression struct {
+	Token token.Token // the [ token
+	Left  Expression
+	Start Expression
+	End   Expression
+	Step  Expression
+}
+
 type HashLiteral struct {
 	Token token.Token
 	Pairs map[Expression]Expression
@@ -123,6 +213,13 @@ func (p *Program) TokenLiteral() string {
 	}
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 
@@ -135,6 +232,7 @@ func (p *Program) String() string {
 
 // let statement
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
+func (ls *LetStatement) Pos() token.Position  { return ls.Token.Pos }
 func (ls *LetStatement) statementNode()       {}
 
 func (ls *LetStatement) String() string {
@@ -153,8 +251,29 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+func (cs *ConstStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ConstStatement) Pos() token.Position  { return cs.Token.Pos }
+func (cs *ConstStatement) statementNode()       {}
+
+func (cs *ConstStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cs.TokenLiteral() + " ")
+	out.WriteString(cs.Name.String())
+	out.WriteString(" = ")
+
+	if cs.Value != nil {
+		out.WriteString(cs.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
 // identifier
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
+func (i *Identifier) Pos() token.Position  { return i.Token.Pos }
 func (i *Identifier) expressionNode()      {}
 
 func (i *Identifier) String() string { return i.Value }
@@ -162,6 +281,7 @@ func (i *Identifier) String() string { return i.Value }
 // return statment
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() token.Position  { return rs.Token.Pos }
 
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
@@ -180,6 +300,7 @@ func (rs *ReturnStatement) String() string {
 // expression statement
 func (es *ExpressionStatement) statementNode()       {}
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() token.Position  { return es.Token.Pos }
 
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
@@ -192,11 +313,19 @@ func (es *ExpressionStatement) String() string {
 // integer literal
 func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
+func (il *IntegerLiteral) Pos() token.Position  { return il.Token.Pos }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+// float literal
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) Pos() token.Position  { return fl.Token.Pos }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 // prefix expression
 func (ps *PrefixExpression) expressionNode()      {}
 func (ps *PrefixExpression) TokenLiteral() string { return ps.Token.Literal }
+func (ps *PrefixExpression) Pos() token.Position  { return ps.Token.Pos }
 func (ps *PrefixExpression) String() string {
 	var out bytes.Buffer
 
@@ -211,6 +340,7 @@ func (ps *PrefixExpression) String() string {
 // infix expression
 func (oe *InfixExpression) expressionNode()      {}
 func (oe *InfixExpression) TokenLiteral() string { return oe.Token.Literal }
+func (oe *InfixExpression) Pos() token.Position  { return oe.Token.Pos }
 
 func (oe *InfixExpression) String() string {
 	var out bytes.Buffer
@@ -227,11 +357,13 @@ func (oe *InfixExpression) String() string {
 // boolean
 func (b *Boolean) expressionNode()      {}
 func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+func (b *Boolean) Pos() token.Position  { return b.Token.Pos }
 func (b *Boolean) String() string       { return b.Token.Literal }
 
 // If Expression
 func (ie *IfExpression) expressionNode()      {}
 func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) Pos() token.Position  { return ie.Token.Pos }
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
 
@@ -248,9 +380,70 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) Pos() token.Position  { return me.Token.Pos }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.Pattern.String()+" => "+arm.Body.String())
+	}
+
+	out.WriteString("match ")
+	out.WriteString(me.Subject.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+func (ap *ArrayPattern) expressionNode()      {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) Pos() token.Position  { return ap.Token.Pos }
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+func (hp *HashPattern) expressionNode()      {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) Pos() token.Position  { return hp.Token.Pos }
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		pairs = append(pairs, key.String()+":"+hp.Values[i].String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
 // Block statements
 func (bs *BlockStatement) statementNode()       {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() token.Position  { return bs.Token.Pos }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 
@@ -261,15 +454,56 @@ func (bs *BlockStatement) String() string {
 	return out.String()
 }
 
+// while statement
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) Pos() token.Position  { return ws.Token.Pos }
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
+
+// for statement
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) Pos() token.Position  { return fs.Token.Pos }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for(")
+	out.WriteString(fs.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
 // Function literal
 func (fl *FunctionLiteral) expressionNode()      {}
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() token.Position  { return fl.Token.Pos }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+	for i, p := range fl.Parameters {
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			params = append(params, p.String()+" = "+fl.Defaults[i].String())
+		} else {
+			params = append(params, p.String())
+		}
+	}
+
+	if fl.Rest != nil {
+		params = append(params, "..."+fl.Rest.String())
 	}
 
 	out.WriteString(fl.TokenLiteral())
@@ -284,6 +518,7 @@ func (fl *FunctionLiteral) String() string {
 // call expression
 func (ce *CallExpression) expressionNode()      {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) Pos() token.Position  { return ce.Token.Pos }
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 
@@ -305,11 +540,31 @@ func (ce *CallExpression) String() string {
 // string
 func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) Pos() token.Position  { return sl.Token.Pos }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) Pos() token.Position  { return is.Token.Pos }
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	for i, exp := range is.Expressions {
+		out.WriteString(is.Strings[i])
+		out.WriteString("${")
+		out.WriteString(exp.String())
+		out.WriteString("}")
+	}
+
+	out.WriteString(is.Strings[len(is.Strings)-1])
+
+	return out.String()
+}
+
 // arrayliteral
 func (al *ArrayLiteral) expressionNode()      {}
 func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+func (al *ArrayLiteral) Pos() token.Position  { return al.Token.Pos }
 func (al *ArrayLiteral) String() string {
 	var out bytes.Buffer
 
@@ -326,9 +581,52 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// assign expression
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) Pos() token.Position  { return ae.Token.Pos }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" " + ae.Operator + " ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// slice expression
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) Pos() token.Position  { return se.Token.Pos }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	if se.Step != nil {
+		out.WriteString(":")
+		out.WriteString(se.Step.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
 // index expression
 func (ie *IndexExpression) expressionNode()      {}
 func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) Pos() token.Position  { return ie.Token.Pos }
 func (ie *IndexExpression) String() string {
 	var out bytes.Buffer
 
@@ -345,6 +643,7 @@ func (ie *IndexExpression) String() string {
 // hash literals
 func (hl *HashLiteral) expressionNode()      {}
 func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+func (hl *HashLiteral) Pos() token.Position  { return hl.Token.Pos }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
@@ -0,0 +1,249 @@
+package evaluator
+
+import (
+	"Mon/object"
+	"math"
+	"math/big"
+)
+
+// CheckedArithmetic makes integer operations that overflow int64 return an
+// error instead of promoting the result to a BigInt.
+var CheckedArithmetic = false
+
+// maxPowerBits caps the size in bits of the result of **, so that a typo can
+// not exhaust memory.
+const maxPowerBits = 1 << 24
+
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+func subInt64(a, b int64) (int64, bool) {
+	c := a - b
+	return c, (c < a) == (b > 0)
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	c := a * b
+
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return c, false
+	}
+
+	return c, c/b == a
+}
+
+func divInt64(a, b int64) (int64, bool) {
+	return a / b, !(a == math.MinInt64 && b == -1)
+}
+
+func negInt64(a int64) (int64, bool) {
+	return -a, a != math.MinInt64
+}
+
+// powInt64 raises a to a non-negative power by repeated squaring.
+func powInt64(a, b int64) (int64, bool) {
+	result := int64(1)
+
+	for b > 0 {
+		var ok bool
+
+		if b&1 == 1 {
+			if result, ok = mulInt64(result, a); !ok {
+				return 0, false
+			}
+		}
+
+		b >>= 1
+
+		if b > 0 {
+			if a, ok = mulInt64(a, a); !ok {
+				return 0, false
+			}
+		}
+	}
+
+	return result, true
+}
+
+func shlInt64(a, b int64) (int64, bool) {
+	if a == 0 {
+		return 0, true
+	}
+
+	if b >= 64 {
+		return 0, false
+	}
+
+	c := a << uint64(b)
+	return c, c>>uint64(b) == a
+}
+
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
+}
+
+func isNumber(obj object.Object) bool {
+	return isInteger(obj) || obj.Type() == object.FLOAT_OBJ
+}
+
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	default:
+		return new(big.Int)
+	}
+}
+
+// normalizeBigInt turns v back into an Integer when it fits in an int64.
+func normalizeBigInt(v *big.Int) object.Object {
+	if v.IsInt64() {
+		return &object.Integer{Value: v.Int64()}
+	}
+	return &object.BigInt{Value: v}
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInt:
+		f, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return f
+	case *object.Float:
+		return obj.Value
+	default:
+		return math.NaN()
+	}
+}
+
+// evalBigIntInfixExpression handles integers of which at least one is, or
+// the result may become, too large for an int64.
+func evalBigIntInfixExpression(
+	operator string,
+	left object.Object, right object.Object,
+) object.Object {
+	leftVal := toBigInt(left)
+	rightVal := toBigInt(right)
+
+	switch operator {
+	case "+":
+		return normalizeBigInt(new(big.Int).Add(leftVal, rightVal))
+	case "-":
+		return normalizeBigInt(new(big.Int).Sub(leftVal, rightVal))
+	case "*":
+		return normalizeBigInt(new(big.Int).Mul(leftVal, rightVal))
+	case "/", "%":
+		if rightVal.Sign() == 0 {
+			return newError("division by zero: %s %s 0", leftVal, operator)
+		}
+		if operator == "%" {
+			return normalizeBigInt(new(big.Int).Rem(leftVal, rightVal))
+		}
+		return normalizeBigInt(new(big.Int).Quo(leftVal, rightVal))
+	case "**":
+		if rightVal.Sign() < 0 && leftVal.Sign() == 0 {
+			return newError("division by zero: 0 ** %s", rightVal)
+		}
+		if rightVal.Sign() < 0 {
+			return &object.Float{Value: math.Pow(toFloat(left), toFloat(right))}
+		}
+		// any power of 0, 1 or -1 is small, however large the exponent
+		if leftVal.CmpAbs(big.NewInt(1)) > 0 &&
+			(!rightVal.IsInt64() || rightVal.Int64() > maxPowerBits/int64(leftVal.BitLen())) {
+			return newError("exponent too large: %s ** %s", leftVal, rightVal)
+		}
+		return normalizeBigInt(new(big.Int).Exp(leftVal, rightVal, nil))
+	case "<<", ">>":
+		if rightVal.Sign() < 0 {
+			return newError("negative shift count: %s %s %s",
+				leftVal, operator, rightVal)
+		}
+		if !rightVal.IsUint64() || rightVal.Uint64() > math.MaxUint32 {
+			return newError("shift count too large: %s %s %s",
+				leftVal, operator, rightVal)
+		}
+		if operator == "<<" {
+			return normalizeBigInt(new(big.Int).Lsh(leftVal, uint(rightVal.Uint64())))
+		}
+		return normalizeBigInt(new(big.Int).Rsh(leftVal, uint(rightVal.Uint64())))
+	case "&":
+		return normalizeBigInt(new(big.Int).And(leftVal, rightVal))
+	case "|":
+		return normalizeBigInt(new(big.Int).Or(leftVal, rightVal))
+	case "^":
+		return normalizeBigInt(new(big.Int).Xor(leftVal, rightVal))
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+// evalFloatInfixExpression handles two floats, or a float and an integer,
+// in which case the integer is converted to a float first.
+func evalFloatInfixExpression(
+	operator string,
+	left object.Object, right object.Object,
+) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/", "%":
+		if rightVal == 0 {
+			return newError("division by zero: %s %s %s",
+				left.Inspect(), operator, right.Inspect())
+		}
+		if operator == "%" {
+			return &object.Float{Value: math.Mod(leftVal, rightVal)}
+		}
+		return &object.Float{Value: leftVal / rightVal}
+	case "**":
+		if leftVal == 0 && rightVal < 0 {
+			return newError("division by zero: %s ** %s",
+				left.Inspect(), right.Inspect())
+		}
+		return &object.Float{Value: math.Pow(leftVal, rightVal)}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
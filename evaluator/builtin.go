@@ -3,8 +3,17 @@ package evaluator
 import (
 	"Mon/object"
 	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
+// maxRoundPlaces is the most decimal places round takes either way; 10 to
+// any larger power does not fit in a float.
+const maxRoundPlaces = 308
+
 var builtin = map[string]*object.Builtin{
 	"len": &object.Builtin{
 		Fn: func(args ...object.Object) object.Object {
@@ -16,7 +25,7 @@ var builtin = map[string]*object.Builtin{
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			default:
 				return newError("argument to `len` not supported, got %s",
 					args[0].Type())
@@ -108,4 +117,155 @@ var builtin = map[string]*object.Builtin{
 			return &object.Array{Elements: newElements}
 		},
 	},
+	"delete": &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments, got=%d, want=%d",
+					len(args), 2)
+			}
+			if args[0].Type() != object.HASH_OBJ {
+				return newError("argument to `delete` must be HASH, got %s",
+					args[0].Type())
+			}
+			hash := args[0].(*object.Hash)
+
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return newError("unusable as hash key: %s", args[1].Type())
+			}
+
+			pair, ok := hash.Pairs[key.HashKey()]
+			if !ok {
+				return NULL
+			}
+
+			delete(hash.Pairs, key.HashKey())
+			return pair.Value
+		},
+	},
+	"float": &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments, got=%d, want=%d",
+					len(args), 1)
+			}
+			switch arg := args[0].(type) {
+			case *object.Integer, *object.BigInt:
+				return &object.Float{Value: toFloat(arg)}
+			case *object.Float:
+				return arg
+			case *object.String:
+				value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+				if err != nil {
+					return newError("could not convert %q to FLOAT", arg.Value)
+				}
+				return &object.Float{Value: value}
+			default:
+				return newError("argument to `float` not supported, got %s",
+					args[0].Type())
+			}
+		},
+	},
+	"int": &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments, got=%d, want=%d",
+					len(args), 1)
+			}
+			switch arg := args[0].(type) {
+			case *object.Integer, *object.BigInt:
+				return arg
+			case *object.Float:
+				return floatToInteger(arg.Value)
+			case *object.String:
+				value, ok := new(big.Int).SetString(strings.TrimSpace(arg.Value), 0)
+				if !ok {
+					return newError("could not convert %q to INTEGER", arg.Value)
+				}
+				return normalizeBigInt(value)
+			default:
+				return newError("argument to `int` not supported, got %s",
+					args[0].Type())
+			}
+		},
+	},
+	"round": &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newError("wrong number of arguments, got=%d, want=1 or 2",
+					len(args))
+			}
+
+			places := int64(0)
+			if len(args) == 2 {
+				p, ok := args[1].(*object.Integer)
+				if !ok {
+					return newError("second argument to `round` must be INTEGER, got %s",
+						args[1].Type())
+				}
+				places = p.Value
+			}
+
+			if places < -maxRoundPlaces || places > maxRoundPlaces {
+				return newError("second argument to `round` must be between %d and %d, got %d",
+					-maxRoundPlaces, maxRoundPlaces, places)
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer, *object.BigInt:
+				return arg
+			case *object.Float:
+				scale := math.Pow(10, float64(places))
+
+				// a float too large to scale has no digits left to round off
+				scaled := arg.Value * scale
+				if math.IsInf(scaled, 0) {
+					return arg
+				}
+
+				return &object.Float{Value: math.Round(scaled) / scale}
+			default:
+				return newError("argument to `round` must be a number, got %s",
+					args[0].Type())
+			}
+		},
+	},
+	"floor": floatBuiltin("floor", math.Floor),
+	"ceil":  floatBuiltin("ceil", math.Ceil),
+}
+
+// floatBuiltin wraps a float function as a builtin that leaves integers
+// unchanged.
+func floatBuiltin(name string, fn func(float64) float64) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments, got=%d, want=%d",
+					len(args), 1)
+			}
+			switch arg := args[0].(type) {
+			case *object.Integer, *object.BigInt:
+				return arg
+			case *object.Float:
+				return &object.Float{Value: fn(arg.Value)}
+			default:
+				return newError("argument to `%s` must be a number, got %s",
+					name, args[0].Type())
+			}
+		},
+	}
+}
+
+func floatToInteger(value float64) object.Object {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return newError("could not convert %s to INTEGER",
+			(&object.Float{Value: value}).Inspect())
+	}
+
+	if value >= math.MaxInt64 || value < math.MinInt64 {
+		v, _ := big.NewFloat(value).Int(nil)
+		return normalizeBigInt(v)
+	}
+
+	return &object.Integer{Value: int64(value)}
 }
@@ -3,7 +3,12 @@ package evaluator
 import (
 	"Mon/ast"
 	"Mon/object"
+	"Mon/token"
 	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
 )
 
 var (
@@ -12,7 +17,23 @@ var (
 	FALSE = &object.Boolean{Value: false}
 )
 
+// LegacyScoping makes blocks run in the environment around them instead of
+// a new enclosed one, so a let inside an if branch or a loop body is still
+// bound after it, as it was before blocks had their own scope.
+var LegacyScoping = false
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := evalNode(node, env)
+
+	// the innermost node an error comes out of is where it is reported
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() && node != nil {
+		err.Pos = node.Pos()
+	}
+
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node.Statements, env)
@@ -21,14 +42,23 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return Eval(node.Expression, env)
 
 	case *ast.IntegerLiteral:
+		if node.Big != nil {
+			return &object.BigInt{Value: node.Big}
+		}
 		return &object.Integer{Value: node.Value}
 
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
+
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -37,6 +67,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalPrefixExpression(node.Operator, right)
 
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
+
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -48,11 +82,20 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalInfixExpression(node.Operator, left, right)
 
 	case *ast.BlockStatement:
-		return evalBlockStatement(node, env)
+		return evalBlockStatement(node, blockEnv(env))
 
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -64,15 +107,34 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val)
+		if err := declare(env, node.Name, val, false); err != nil {
+			return err
+		}
+
+	case *ast.ConstStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		if err := declare(env, node.Name, val, true); err != nil {
+			return err
+		}
 
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+
 	case *ast.FunctionLiteral:
-		params := node.Parameters
-		body := node.Body
-		return &object.Function{Parameters: params, Body: body, Env: env}
+		return &object.Function{
+			Name:       node.Name,
+			Parameters: node.Parameters,
+			Defaults:   node.Defaults,
+			Rest:       node.Rest,
+			Body:       node.Body,
+			Env:        env,
+		}
 
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
@@ -83,7 +145,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		return applyFunction(function, args, node.Function.Pos())
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
@@ -100,6 +162,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return index
 		}
 		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
 	}
@@ -124,6 +188,14 @@ func evalProgram(stmt []ast.Statement, env *object.Environment) object.Object {
 	return result
 }
 
+// blockEnv returns the environment a block nested in env runs in.
+func blockEnv(env *object.Environment) *object.Environment {
+	if LegacyScoping {
+		return env
+	}
+	return object.NewEnclosedEnvironment(env)
+}
+
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
 
@@ -156,6 +228,8 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 		return evalBangOperatorExpression(right)
 	case "-":
 		return evalMinusPrefixOperatorExpression(right)
+	case "~":
+		return evalTildePrefixOperatorExpression(right)
 	default:
 		return newError("unknown operator: %s%s", operator, right.Type())
 	}
@@ -175,12 +249,34 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Neg(right.Value))
+	case *object.Integer:
+		value, ok := negInt64(right.Value)
+		if ok {
+			return &object.Integer{Value: value}
+		}
+		if CheckedArithmetic {
+			return newError("integer overflow: -%s", right.Inspect())
+		}
+		return normalizeBigInt(new(big.Int).Neg(toBigInt(right)))
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
+}
 
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
+func evalTildePrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Not(right.Value))
+	default:
+		return newError("unknown operator: ~%s", right.Type())
+	}
 }
 
 func evalInfixExpression(
@@ -190,8 +286,15 @@ func evalInfixExpression(
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isInteger(left) && isInteger(right):
+		return evalBigIntInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
+	case operator == "*" && left.Type() == object.STRING_OBJ && isInteger(right),
+		operator == "*" && isInteger(left) && right.Type() == object.STRING_OBJ:
+		return evalStringRepetition(left, right)
 	case operator == "==":
 		return nativeBoolToBooleanObject(left == right)
 	case operator == "!=":
@@ -205,6 +308,176 @@ func evalInfixExpression(
 	}
 }
 
+// declare binds name in env, refusing to replace a const binding of the
+// same environment.
+func declare(
+	env *object.Environment,
+	name *ast.Identifier,
+	val object.Object,
+	constant bool,
+) *object.Error {
+	if env.IsConst(name.Value) {
+		err := newError("cannot redeclare constant: %s", name.Value)
+		err.Pos = name.Pos()
+		return err
+	}
+
+	if constant {
+		env.SetConst(name.Value, val)
+	} else {
+		env.Set(name.Value, val)
+	}
+
+	return nil
+}
+
+func evalAssignExpression(
+	node *ast.AssignExpression,
+	env *object.Environment,
+) object.Object {
+	if target, ok := node.Target.(*ast.IndexExpression); ok {
+		return evalIndexAssignExpression(node, target, env)
+	}
+
+	ident := node.Target.(*ast.Identifier)
+
+	current, ok := env.Get(ident.Value)
+	if !ok {
+		err := newError("assignment to undeclared identifier: %s", ident.Value)
+		err.Pos = ident.Pos()
+		return err
+	}
+
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	if node.Operator != "=" {
+		val = evalInfixExpression(strings.TrimSuffix(node.Operator, "="), current, val)
+		if isError(val) {
+			return val
+		}
+	}
+
+	if _, ok := env.Assign(ident.Value, val); !ok {
+		err := newError("cannot assign to constant: %s", ident.Value)
+		err.Pos = ident.Pos()
+		return err
+	}
+
+	return val
+}
+
+// evalIndexAssignExpression stores a value into an array element or a hash
+// entry in place, so every binding of the array or hash sees the change.
+func evalIndexAssignExpression(
+	node *ast.AssignExpression,
+	target *ast.IndexExpression,
+	env *object.Environment,
+) object.Object {
+	left := Eval(target.Left, env)
+	if isError(left) {
+		return left
+	}
+	index := Eval(target.Index, env)
+	if isError(index) {
+		return index
+	}
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	result := setIndex(left, index, strings.TrimSuffix(node.Operator, "="), val)
+
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = target.Pos()
+	}
+
+	return result
+}
+
+// setIndex assigns val to left[index]. A non-empty operator makes it a
+// compound assignment, which needs the element to exist already.
+func setIndex(left, index object.Object, operator string, val object.Object) object.Object {
+	switch container := left.(type) {
+	case *object.Array:
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return newError("array index must be INTEGER, got %s", index.Type())
+		}
+
+		length := int64(len(container.Elements))
+		if idx.Value < 0 || idx.Value >= length {
+			return newError("index out of range: %d (length %d)", idx.Value, length)
+		}
+
+		if operator != "" {
+			val = evalInfixExpression(operator, container.Elements[idx.Value], val)
+			if isError(val) {
+				return val
+			}
+		}
+
+		container.Elements[idx.Value] = val
+		return val
+
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+
+		hashed := key.HashKey()
+
+		if operator != "" {
+			pair, ok := container.Pairs[hashed]
+			if !ok {
+				return newError("key not found: %s", index.Inspect())
+			}
+
+			val = evalInfixExpression(operator, pair.Value, val)
+			if isError(val) {
+				return val
+			}
+		}
+
+		container.Pairs[hashed] = object.HashPair{Key: index, Value: val}
+		return val
+
+	default:
+		return newError("index assignment not supported: %s", left.Type())
+	}
+}
+
+// evalLogicalExpression only evaluates the right operand when the left one
+// does not already decide the result.
+func evalLogicalExpression(
+	node *ast.InfixExpression,
+	env *object.Environment,
+) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	if node.Operator == "&&" && !isTruthy(left) {
+		return FALSE
+	}
+
+	if node.Operator == "||" && isTruthy(left) {
+		return TRUE
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+
+	return nativeBoolToBooleanObject(isTruthy(right))
+}
+
 func evalIntegerInfixExpression(
 	operator string,
 	left object.Object, right object.Object,
@@ -212,19 +485,70 @@ func evalIntegerInfixExpression(
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
+	var (
+		result int64
+		ok     bool
+	)
+
 	switch operator {
 	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
+		result, ok = addInt64(leftVal, rightVal)
 	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
+		result, ok = subInt64(leftVal, rightVal)
 	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
-	case "/":
-		return &object.Integer{Value: leftVal / rightVal}
+		result, ok = mulInt64(leftVal, rightVal)
+	case "/", "%":
+		if rightVal == 0 {
+			return newError("division by zero: %d %s 0", leftVal, operator)
+		}
+		result, ok = divInt64(leftVal, rightVal)
+		if operator == "%" {
+			result, ok = leftVal%rightVal, true
+		}
+	case "**":
+		if rightVal < 0 && leftVal == 0 {
+			return newError("division by zero: 0 ** %d", rightVal)
+		}
+		if rightVal < 0 {
+			return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
+		}
+		result, ok = powInt64(leftVal, rightVal)
+	case "<<", ">>":
+		if rightVal < 0 {
+			return newError("negative shift count: %d %s %d",
+				leftVal, operator, rightVal)
+		}
+		if operator == "<<" {
+			result, ok = shlInt64(leftVal, rightVal)
+		} else {
+			result, ok = leftVal>>uint64(rightVal), true
+		}
+	}
+
+	switch operator {
+	case "+", "-", "*", "/", "%", "**", "<<", ">>":
+		if ok {
+			return &object.Integer{Value: result}
+		}
+		if CheckedArithmetic {
+			return newError("integer overflow: %d %s %d",
+				leftVal, operator, rightVal)
+		}
+		return evalBigIntInfixExpression(operator, left, right)
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	case "==":
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
@@ -252,6 +576,125 @@ func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Obje
 	}
 }
 
+func evalWhileStatement(ws *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(ws.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		result := Eval(ws.Body, env)
+		if isReturnOrError(result) {
+			return result
+		}
+	}
+}
+
+func evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var result object.Object
+
+	err := iterate(iterable, func(item object.Object) bool {
+		// each iteration gets its own binding of the variable, so closures
+		// created in the body see the item of their own iteration
+		scope := blockEnv(env)
+
+		if err := declare(scope, fs.Variable, item, false); err != nil {
+			result = err
+			return false
+		}
+		result = evalBlockStatement(fs.Body, scope)
+		return !isReturnOrError(result)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if isReturnOrError(result) {
+		return result
+	}
+
+	return NULL
+}
+
+// iterate calls fn with every item of an array, every key of a hash, every
+// character of a string, or every integer from 0 up to n, until fn returns
+// false.
+func iterate(iterable object.Object, fn func(object.Object) bool) *object.Error {
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for _, el := range iterable.Elements {
+			if !fn(el) {
+				return nil
+			}
+		}
+	case *object.Hash:
+		for _, pair := range sortedPairs(iterable) {
+			if !fn(pair.Key) {
+				return nil
+			}
+		}
+	case *object.String:
+		for _, ch := range iterable.Value {
+			if !fn(&object.String{Value: string(ch)}) {
+				return nil
+			}
+		}
+	case *object.Integer:
+		for i := int64(0); i < iterable.Value; i++ {
+			if !fn(&object.Integer{Value: i}) {
+				return nil
+			}
+		}
+	default:
+		return newError("not iterable: %s", iterable.Type())
+	}
+
+	return nil
+}
+
+// sortedPairs returns the pairs of a hash ordered by key, so iterating over
+// a hash is repeatable.
+func sortedPairs(hash *object.Hash) []object.HashPair {
+	pairs := make([]object.HashPair, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i].Key, pairs[j].Key
+
+		if a.Type() != b.Type() {
+			return a.Type() < b.Type()
+		}
+
+		if isNumber(a) {
+			return evalInfixExpression("<", a, b) == TRUE
+		}
+
+		return a.Inspect() < b.Inspect()
+	})
+
+	return pairs
+}
+
+func isReturnOrError(obj object.Object) bool {
+	if obj != nil {
+		rt := obj.Type()
+		return rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ
+	}
+	return false
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj {
 	case NULL:
@@ -310,29 +753,148 @@ func evalExpressions(
 	return result
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
-	switch function := fn.(type) {
-	case *object.Function:
-		extendedEnv := extendFunctionEnv(function, args)
-		evaluated := Eval(function.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
-	case *object.Builtin:
-		return function.Fn(args...)
+// maxTailFrames caps how many of the calls in a chain of tail calls are
+// kept for a stack trace, so the trace of deep tail recursion stays small.
+const maxTailFrames = 10
+
+// applyFunction calls fn and then keeps making the tail calls it returns.
+// The outermost calls of the chain and the last one end up in the stack
+// trace of an error; the ones between are only counted.
+func applyFunction(
+	fn object.Object,
+	args []object.Object,
+	callPos token.Position,
+) object.Object {
+	var (
+		tails  []object.Frame
+		elided int
+	)
+
+	for {
+		var (
+			evaluated object.Object
+			frame     *object.Frame
+		)
+
+		switch function := fn.(type) {
+		case *object.Function:
+			frame = &object.Frame{Function: functionName(function), CallPos: callPos}
+
+			if err := checkArity(function, args); err != nil {
+				err.Pos = callPos
+				evaluated = err
+			} else if extendedEnv, err := extendFunctionEnv(function, args); err != nil {
+				evaluated = err
+			} else {
+				evaluated = unwrapReturnValue(evalTailBlock(function.Body, extendedEnv, true))
+			}
+
+			if tc, ok := evaluated.(*tailCall); ok {
+				if len(tails) < maxTailFrames {
+					tails = append(tails, *frame)
+				} else {
+					elided++
+				}
+
+				fn, args, callPos = tc.fn, tc.args, tc.callPos
+				continue
+			}
+		case *object.Builtin:
+			evaluated = function.Fn(args...)
+		default:
+			evaluated = newError("not a function: %s", fn.Type())
+		}
+
+		if err, ok := evaluated.(*object.Error); ok {
+			if !err.Pos.IsValid() {
+				err.Pos = callPos
+			}
+
+			if frame != nil {
+				err.Stack = append(err.Stack, *frame)
+			}
+
+			for i := len(tails) - 1; i >= 0; i-- {
+				err.Stack = append(err.Stack, tails[i])
+			}
+
+			if elided > 0 {
+				err.Stack[len(err.Stack)-len(tails)].Elided = elided
+			}
+		}
+
+		return evaluated
+	}
+}
+
+func functionName(fn *object.Function) string {
+	if fn.Name == "" {
+		return "<anonymous>"
+	}
+	return fn.Name
+}
+
+func checkArity(fn *object.Function, args []object.Object) *object.Error {
+	required := 0
+	for i := range fn.Parameters {
+		if i >= len(fn.Defaults) || fn.Defaults[i] == nil {
+			required = i + 1
+		}
+	}
+
+	var want string
+
+	switch {
+	case len(args) >= required && (fn.Rest != nil || len(args) <= len(fn.Parameters)):
+		return nil
+	case fn.Rest != nil:
+		want = fmt.Sprintf(">=%d", required)
+	case required != len(fn.Parameters):
+		want = fmt.Sprintf("=%d..%d", required, len(fn.Parameters))
 	default:
-		return newError("not a function: %s", fn.Type())
+		want = fmt.Sprintf("=%d", required)
 	}
+
+	if fn.Name == "" {
+		return newError("wrong number of arguments: want%s, got=%d",
+			want, len(args))
+	}
+
+	return newError("wrong number of arguments to `%s`: want%s, got=%d",
+		fn.Name, want, len(args))
 }
 
+// extendFunctionEnv binds the arguments of a call. Defaults of missing
+// arguments are evaluated in the new environment, so they can refer to the
+// parameters before them.
 func extendFunctionEnv(
 	fn *object.Function,
 	args []object.Object,
-) *object.Environment {
+) (*object.Environment, *object.Error) {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
 	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Value, args[paramIdx])
+		if paramIdx < len(args) {
+			env.Set(param.Value, args[paramIdx])
+			continue
+		}
+
+		value := Eval(fn.Defaults[paramIdx], env)
+		if err, ok := value.(*object.Error); ok {
+			return nil, err
+		}
+		env.Set(param.Value, value)
 	}
-	return env
+
+	if fn.Rest != nil {
+		rest := []object.Object{}
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+		env.Set(fn.Rest.Value, &object.Array{Elements: rest})
+	}
+
+	return env, nil
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -347,21 +909,70 @@ func evalStringInfixExpression(
 	operator string,
 	left object.Object, right object.Object,
 ) object.Object {
-	if operator != "+" {
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	default:
 		return newError("unknown operator: %s %s %s",
 			left.Type(), operator, right.Type())
 	}
+}
 
-	leftVal := left.(*object.String).Value
-	rightVal := right.(*object.String).Value
+// maxRepeatedString caps the length of a string made with *, so that a
+// typo can not exhaust memory.
+const maxRepeatedString = 1 << 30
+
+func evalStringRepetition(left, right object.Object) object.Object {
+	str, count := left, right
+	if isInteger(left) {
+		str, count = right, left
+	}
+
+	value := str.(*object.String).Value
+
+	var n int64
+	switch count := count.(type) {
+	case *object.Integer:
+		n = count.Value
+	case *object.BigInt:
+		// too far from zero either way; only the sign matters
+		n = int64(count.Value.Sign()) * math.MaxInt64
+	}
 
-	return &object.String{Value: leftVal + rightVal}
+	if n < 0 {
+		return newError("negative repeat count: %s * %s",
+			left.Type(), right.Type())
+	}
+
+	if n > 0 && int64(len(value)) > maxRepeatedString/n {
+		return newError("string repetition too large: %s * %s",
+			left.Type(), right.Type())
+	}
+
+	return &object.String{Value: strings.Repeat(value, int(n))}
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
@@ -380,6 +991,41 @@ func evalArrayIndexExpression(left, index object.Object) object.Object {
 	return arrayObject.Elements[idx]
 }
 
+func evalInterpolatedString(
+	node *ast.InterpolatedString,
+	env *object.Environment,
+) object.Object {
+	var out strings.Builder
+
+	for i, exp := range node.Expressions {
+		out.WriteString(node.Strings[i])
+
+		value := Eval(exp, env)
+		if isError(value) {
+			return value
+		}
+
+		out.WriteString(value.Inspect())
+	}
+
+	out.WriteString(node.Strings[len(node.Strings)-1])
+
+	return &object.String{Value: out.String()}
+}
+
+// evalStringIndexExpression counts in code points, not bytes, so "héllo"[1]
+// is "é".
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	idx := index.(*object.Integer).Value
+	max := int64(len(runes) - 1)
+
+	if idx < 0 || idx > max {
+		return NULL
+	}
+	return &object.String{Value: string(runes[idx])}
+}
+
 func evalHashLiteral(
 	node *ast.HashLiteral,
 	env *object.Environment,
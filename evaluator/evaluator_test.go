@@ -4,6 +4,8 @@ import (
 	"Mon/lexer"
 	"Mon/object"
 	"Mon/parser"
+	"runtime/debug"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +37,186 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestEvalExtendedIntegerOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"5 ** 0", 1},
+		{"12 & 10", 8},
+		{"12 | 10", 14},
+		{"12 ^ 10", 6},
+		{"~5", -6},
+		{"1 << 10", 1024},
+		{"1024 >> 3", 128},
+		{"-16 >> 2", -4},
+		{"1 + 2 * 3 % 4", 3},
+		{"1 | 2 & 3", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testIntegerObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestEvalExtendedOperatorResults(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 <= 2", "true"},
+		{"2 <= 2", "true"},
+		{"3 <= 2", "false"},
+		{"2 >= 3", "false"},
+		{"1.5 >= 1.5", "true"},
+		{"100000000000000000000 >= 5", "true"},
+		{"2 ** -1", "0.5"},
+		{"2.0 ** 3", "8.0"},
+		{"7.5 % 2", "1.5"},
+		{"2 ** 64", "18446744073709551616"},
+		{"3 ** 40", "12157665459056928801"},
+		{"1 << 64", "18446744073709551616"},
+		{"(1 << 64) >> 60", "16"},
+		{"(1 << 64) % 10", "6"},
+		{"(1 << 64) | 1", "18446744073709551617"},
+		{"~(1 << 64)", "-18446744073709551617"},
+		{"5 % 0", "ERROR: 1:3: division by zero: 5 % 0"},
+		{"5.5 % 0", "ERROR: 1:5: division by zero: 5.5 % 0"},
+		{"1 << -1", "ERROR: 1:3: negative shift count: 1 << -1"},
+		{"2 ** 100000000000", "ERROR: 1:3: exponent too large: 2 ** 100000000000"},
+		{"(1 << 64) ** 100000000", "ERROR: 1:11: exponent too large: 18446744073709551616 ** 100000000"},
+		{"1 ** 100000000000000000000", "1"},
+		{"(-1) ** 100000000001", "-1"},
+		{"0 ** 100000000000", "0"},
+		{"1.5 & 1", "ERROR: 1:5: unknown operator: FLOAT & INTEGER"},
+		{"~true", "ERROR: 1:1: unknown operator: ~BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"2.5", 2.5},
+		{"-2.5", -2.5},
+		{"1.5 + 1.5", 3},
+		{"1 + 0.5", 1.5},
+		{"0.5 + 1", 1.5},
+		{"10 / 4.0", 2.5},
+		{"2.5 * 2", 5},
+		{"1e3 - 1", 999},
+		{"(1 + 2) * 1.5", 4.5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testFloatObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestEvalMixedNumberComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 == 1.0", true},
+		{"1.5 > 1", true},
+		{"2 < 1.5", false},
+		{"0.1 + 0.2 != 0.3", true},
+		{"2.0 == 2.0", true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2.0", "2.0"},
+		{"1.5 * 2", "3.0"},
+		{"0.25", "0.25"},
+		{"1e21", "1e+21"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect() for %q. want=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestFloatBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"float(2)", 2.0},
+		{`float("2.5")`, 2.5},
+		{"int(3.99)", 3},
+		{"int(-3.99)", -3},
+		{`int("42")`, 42},
+		{"round(2.5)", 3.0},
+		{"round(2.345, 2)", 2.35},
+		{"round(7)", 7},
+		{"floor(-1.5)", -2.0},
+		{"ceil(1.2)", 2.0},
+		{`float("abc")`, `could not convert "abc" to FLOAT`},
+		{"int(true)", "argument to `int` not supported, got BOOLEAN"},
+		{`floor("1")`, "argument to `floor` must be a number, got STRING"},
+		{"1.0 / 0", "division by zero: 1.0 / 0"},
+		{"round(1.5, 308)", 1.5},
+		{"round(1234.5, -2)", 1200.0},
+		{"round(1e300, 100)", 1e300},
+		{"round(1.5, 400)", "second argument to `round` must be between -308 and 308, got 400"},
+		{"round(1.5, -309)", "second argument to `round` must be between -308 and 308, got -309"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q",
+					expected, errObj.Message)
+			}
+		}
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -98,6 +280,36 @@ func TestStringConcatenation(t *testing.T) {
 
 }
 
+func TestInterpolatedString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let name = "Bob"; "hello ${name}"`, "hello Bob"},
+		{`let age = 41; "you are ${age + 1}!"`, "you are 42!"},
+		{`"${1.5} ${true} ${[1, "a"]} ${if (false) { 1 }}"`, "1.5 true [1, a] null"},
+		{`"${"inner ${1 + 1}"}"`, "inner 2"},
+		{`let f = fn(x) { "<${x}>" }; f(f(1))`, "<<1>>"},
+		{`"\${x}"`, "${x}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String for %q, got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if str.Value != tt.expected {
+			t.Errorf("wrong value for %q. want=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+
+	testResult(t, testEval(`"a ${1 + true} b"`), "type mismatch: INTEGER + BOOLEAN")
+}
+
 func TestBangOperator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -118,6 +330,31 @@ func TestBangOperator(t *testing.T) {
 	}
 }
 
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true && true", true},
+		{"true && false", false},
+		{"false && true", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"1 && 0", true},
+		{"!true || true && false", false},
+		{"false && undefined", false},
+		{"true || undefined", true},
+		{"let f = fn() { 1 / 0 }; false && f()", false},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestIfElseExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -222,6 +459,18 @@ func TestErrorHandling(t *testing.T) {
 			`{"name": "Monkey"}[fn(x) { x }];`,
 			"unusable as hash key: FUNCTION",
 		},
+		{
+			"let add = fn(a, b) { a + b }; add(1);",
+			"wrong number of arguments to `add`: want=2, got=1",
+		},
+		{
+			"fn(a, b) { a }(1, 2, 3)",
+			"wrong number of arguments: want=2, got=3",
+		},
+		{
+			"let f = fn() { 1 }; let g = fn(x) { f(x) }; g(1)",
+			"wrong number of arguments to `f`: want=0, got=1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,6 +492,421 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5 + true", "ERROR: 1:3: type mismatch: INTEGER + BOOLEAN"},
+		{"let a = 1;\nlet b = a;\n  foobar", "ERROR: 3:3: identifier not found: foobar"},
+		{"let f = fn(x) {\n  -x\n};\nf(true)", "ERROR: 2:3: unknown operator: -BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+
+		if !ok {
+			t.Errorf("no error object returned. got=%T (%+v)",
+				evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Inspect() != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q",
+				tt.expected, errObj.Inspect())
+		}
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	input := `
+let inner = fn(x) {
+  x + true
+};
+let outer = fn(y) { inner(y) };
+fn(z) { outer(z) }(1)
+`
+
+	evaluated := testEval(input)
+
+	errObj, ok := evaluated.(*object.Error)
+
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expected := []struct {
+		function string
+		callPos  string
+	}{
+		{"inner", "5:21"},
+		{"outer", "6:9"},
+		{"<anonymous>", "6:1"},
+	}
+
+	if len(errObj.Stack) != len(expected) {
+		t.Fatalf("stack has wrong number of frames. want=%d, got=%d",
+			len(expected), len(errObj.Stack))
+	}
+
+	for i, frame := range expected {
+		if errObj.Stack[i].Function != frame.function {
+			t.Errorf("stack[%d] has wrong function. want=%q, got=%q",
+				i, frame.function, errObj.Stack[i].Function)
+		}
+
+		if errObj.Stack[i].CallPos.String() != frame.callPos {
+			t.Errorf("stack[%d] has wrong call position. want=%q, got=%q",
+				i, frame.callPos, errObj.Stack[i].CallPos)
+		}
+	}
+}
+
+func TestTailCallStackTrace(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let f = fn(x) { x }; let g = fn() { f() }; g()`,
+			"\tin f, called at 1:37\n\tin g, called at 1:44\n"},
+		{`let f = fn(n) { if (n == 0) { n + true } else { f(n - 1) } }; f(100)`,
+			"\tin f, called at 1:49\n\t... 90 more tail calls ...\n" +
+				strings.Repeat("\tin f, called at 1:49\n", 9) +
+				"\tin f, called at 1:63\n"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T (%+v)",
+				tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.StackTrace() != tt.expected {
+			t.Errorf("wrong stack trace for %q. want=%q, got=%q",
+				tt.input, tt.expected, errObj.StackTrace())
+		}
+	}
+}
+
+func TestTailCalls(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let count = fn(n, acc) { if (n == 0) { acc } else { count(n - 1, acc + 1) } };
+count(100000, 0)`, "100000"},
+		{`let count = fn(n, acc) { if (n == 0) { return acc; } return count(n - 1, acc + 1); };
+count(100000, 0)`, "100000"},
+		{`let even = fn(n) { if (n == 0) { true } else { odd(n - 1) } };
+let odd = fn(n) { if (n == 0) { false } else { even(n - 1) } };
+even(100001)`, "false"},
+		{`let f = fn(n) { if (n > 0) { return f(n - 1); } len(1) }; f(5)`,
+			"ERROR: 1:49: argument to `len` not supported, got INTEGER"},
+		{`let f = fn(n) { if (n > 0) { return f(n - 1); } 0 }; f(100000)`, "0"},
+		{`let f = fn(n) { if (n > 0) { if (n % 2 == 0) { return f(n - 1); } else { return f(n - 1); } } "done" }; f(100000)`,
+			"done"},
+		{`let f = fn(n) { match (n) { 0 => 0, _ => if (true) { return f(n - 1) } }; "done" }; f(100000)`,
+			"done"},
+		{`let g = fn() { 1 }; let f = fn() { if (true) { g() }; 2 }; f()`, "2"},
+		{`let f = fn(x) { x }; return f(7);`, "7"},
+		{`let f = fn() { g() }; f()`, "ERROR: 1:16: identifier not found: g"},
+		{`let f = fn(x) { x }; let g = fn() { f() }; g()`,
+			"ERROR: 1:37: wrong number of arguments to `f`: want=1, got=0"},
+		{`let g = fn() { 1 }; let f = fn() { "${ if (true) { return g() } }" }; f()`, "1"},
+		{`let g = fn() { 1 }; let f = fn() { [if (true) { return g() }] }; f()`, "[1]"},
+		{`let g = fn() { 1 }; let f = fn() { push([], if (true) { return g() }) }; f()`, "[1]"},
+	}
+
+	// without tail calls, 100000 nested calls need far more stack than this
+	defer debug.SetMaxStack(debug.SetMaxStack(16 << 20))
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 / 0", "division by zero: 1 / 0"},
+		{"let x = 0; 10 / x", "division by zero: 10 / 0"},
+		{"let f = fn(n) { 100 / n }; f(5) + f(0)", "division by zero: 100 / 0"},
+		{"0 ** -1", "division by zero: 0 ** -1"},
+		{"0 ** -100000000000000000000", "division by zero: 0 ** -100000000000000000000"},
+		{"0.0 ** -0.5", "division by zero: 0.0 ** -0.5"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		input     string
+		unchecked string
+		checked   string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808", "integer overflow: 9223372036854775807 + 1"},
+		{"-9223372036854775807 - 2", "-9223372036854775809", "integer overflow: -9223372036854775807 - 2"},
+		{"4611686018427387904 * 2", "9223372036854775808", "integer overflow: 4611686018427387904 * 2"},
+		{"let min = -9223372036854775807 - 1; min / -1", "9223372036854775808", "integer overflow: -9223372036854775808 / -1"},
+		{"let min = -9223372036854775807 - 1; -min", "9223372036854775808", "integer overflow: --9223372036854775808"},
+		{"9223372036854775806 + 1", "9223372036854775807", ""},
+		{"-4611686018427387904 * 2", "-9223372036854775808", ""},
+		{"2 ** 63", "9223372036854775808", "integer overflow: 2 ** 63"},
+		{"1 << 63", "9223372036854775808", "integer overflow: 1 << 63"},
+		{"-2 ** 63", "-9223372036854775808", "integer overflow: 2 ** 63"},
+	}
+
+	defer func() { CheckedArithmetic = false }()
+
+	for _, tt := range tests {
+		CheckedArithmetic = false
+		evaluated := testEval(tt.input)
+
+		if evaluated.Inspect() != tt.unchecked {
+			t.Errorf("wrong result for %q. want=%s, got=%s",
+				tt.input, tt.unchecked, evaluated.Inspect())
+		}
+
+		CheckedArithmetic = true
+		evaluated = testEval(tt.input)
+
+		if tt.checked == "" {
+			if evaluated.Inspect() != tt.unchecked {
+				t.Errorf("wrong result for %q. want=%s, got=%s",
+					tt.input, tt.unchecked, evaluated.Inspect())
+			}
+			continue
+		}
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T (%+v)",
+				tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.checked {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.checked, errObj.Message)
+		}
+	}
+}
+
+func TestBigIntegers(t *testing.T) {
+	tests := []struct {
+		input        string
+		expected     string
+		expectedType object.ObjectType
+	}{
+		{"100000000000000000000", "100000000000000000000", object.BIGINT_OBJ},
+		{"100000000000000000000 - 99999999999999999999", "1", object.INTEGER_OBJ},
+		{"-100000000000000000000 / 10", "-10000000000000000000", object.BIGINT_OBJ},
+		{"let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } }; fact(25)",
+			"15511210043330985984000000", object.BIGINT_OBJ},
+		{"100000000000000000000 > 5", "true", object.BOOLEAN_OBJ},
+		{"100000000000000000000 == 100000000000000000000", "true", object.BOOLEAN_OBJ},
+		{"100000000000000000000 * 0.5", "5e+19", object.FLOAT_OBJ},
+		{`int("100000000000000000000") + 1`, "100000000000000000001", object.BIGINT_OBJ},
+		{"int(1e20)", "100000000000000000000", object.BIGINT_OBJ},
+		{"float(100000000000000000000)", "1e+20", object.FLOAT_OBJ},
+		{"100000000000000000000 / 0", "ERROR: 1:23: division by zero: 100000000000000000000 / 0", object.ERROR_OBJ},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		if evaluated.Type() != tt.expectedType {
+			t.Errorf("wrong type for %q. want=%s, got=%s",
+				tt.input, tt.expectedType, evaluated.Type())
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestBigIntegerHashKey(t *testing.T) {
+	input := `
+let h = {100000000000000000000: "big", 1: "small"};
+[h[50000000000000000000 * 2], h[1]]
+`
+	evaluated := testEval(input)
+
+	if evaluated.Inspect() != "[big, small]" {
+		t.Errorf("wrong result. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestFloatHashKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"{0.0: 1}[-0.0]", 1},
+		{"{1.0: 2}[1]", 2},
+		{"{1: 3}[1.0]", 3},
+		{"{1e20: 4}[100000000000000000000]", 4},
+		{"{1.5: 5}[1.5]", 5},
+		{"{1.5: 6}[1]", nil},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestElseIfExpression(t *testing.T) {
+	input := `
+let sign = fn(n) {
+  if (n < 0) { -1 } else if (n == 0) { 0 } else if (n < 10) { 1 } else { 10 }
+};
+[sign(-5), sign(0), sign(3), sign(50)]
+`
+	testResult(t, testEval(input), "[-1, 0, 1, 10]")
+	testResult(t, testEval("if (false) { 1 } else if (false) { 2 }"), nil)
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"match (2) { 1 => 10, 2 => 20, _ => 30 }", 20},
+		{"match (5) { 1 => 10, 2 => 20, _ => 30 }", 30},
+		{"match (5) { 1 => 10 }", nil},
+		{"match (2.0) { 2 => 1, _ => 0 }", 1},
+		{"match (-3) { -3 => 1, _ => 0 }", 1},
+		{`match ("b") { "a" => 1, "b" => 2 }`, 2},
+		{`match ("1") { 1 => 1, _ => 0 }`, 0},
+		{"match (true) { false => 0, true => 1 }", 1},
+		{"match (7) { n => n * 2, 7 => 0 }", 14},
+		{"match ([]) { [] => 0, _ => 1 }", 0},
+		{"match ([1, 2]) { [a] => a, [a, b] => a + b }", 3},
+		{"match ([1, 2, 3]) { [1, ...rest] => rest }", "[2, 3]"},
+		{"match ([1]) { [x, ...rest] => rest }", "[]"},
+		{"match ([[1, 2], 3]) { [[a, _], b] => a + b }", 4},
+		{"match ([1, 2]) { [2, _] => 0, [_, 2] => 1 }", 1},
+		{`match ({"x": 1, "y": 2}) { {"x": 0} => 0, {"x": x, "y": y} => x + y }`, 3},
+		{`match ({"k": [1]}) { {"k": [v]} => v }`, 1},
+		{`match ({}) { {"x": _} => 1, {} => 2 }`, 2},
+		{`match ([1]) { {} => 1, _ => 2 }`, 2},
+		{"let x = 1; match (2) { x => x }; x", 1},
+		{"let f = fn(xs, acc) { match (xs) { [] => acc, [x, ...rest] => f(rest, acc + x) } }; f([1, 2, 3, 4], 0)", 10},
+		{"match (1 + true) { _ => 0 }", "type mismatch: INTEGER + BOOLEAN"},
+		{"match (1) { _ => -true }", "unknown operator: -BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let i = 0; while (i < 10) { i = i + 1; }; i", 10},
+		{"let i = 0; while (false) { i = 1; }; i", 0},
+		{"while (false) { 1 }", nil},
+		{"let f = fn() { let i = 0; while (true) { if (i == 3) { return i; } i += 1; } }; f()", 3},
+		{"while (true) { -true }", "unknown operator: -BOOLEAN"},
+		{"while (1 + true) { }", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { sum += x; }; sum", 10},
+		{"let n = 0; for (i in 5) { n += i; }; n", 10},
+		{"let n = 0; for (i in 0) { n += 1; }; n", 0},
+		{`let s = ""; for (c in "abc") { s = c + s; }; s`, "cba"},
+		{`let s = ""; for (k in {"b": 2, "a": 1, "c": 3}) { s += k; }; s`, "abc"},
+		{`let keys = []; for (k in {10: 1, 2: 1, 1: 1}) { keys = push(keys, k); }; keys`, "[1, 2, 10]"},
+		{"let f = fn(xs) { for (x in xs) { if (x > 2) { return x; } } }; f([1, 2, 3, 4])", 3},
+		{"for (x in [1]) { x }", nil},
+		{"for (x in true) { x }", "not iterable: BOOLEAN"},
+		{"for (x in [1, 2]) { x + true }", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBlockScoping(t *testing.T) {
+	tests := []struct {
+		input  string
+		scoped string
+		legacy string
+	}{
+		{"let x = 1; if (true) { let x = 2; }; x", "1", "2"},
+		{"let x = 1; if (false) { 0 } else { let x = 3; }; x", "1", "3"},
+		{"let x = 1; if (true) { x = 2; }; x", "2", "2"},
+		{"if (true) { let y = 2; }; y", "ERROR: 1:27: identifier not found: y", "2"},
+		{"let i = 0; while (i < 2) { let j = i; i += 1; }; j", "ERROR: 1:50: identifier not found: j", "1"},
+		{"for (x in [1, 2]) { }; x", "ERROR: 1:24: identifier not found: x", "2"},
+		{"let fs = []; for (x in [1, 2]) { fs = push(fs, fn() { x }); }; fs[0]()", "1", "2"},
+		{"let f = fn(n) { if (n > 0) { let n = 0; n } else { n } }; f(5)", "0", "0"},
+	}
+
+	defer func() { LegacyScoping = false }()
+
+	for _, tt := range tests {
+		LegacyScoping = false
+		evaluated := testEval(tt.input)
+
+		if evaluated.Inspect() != tt.scoped {
+			t.Errorf("wrong result for %q. want=%s, got=%s",
+				tt.input, tt.scoped, evaluated.Inspect())
+		}
+
+		LegacyScoping = true
+		evaluated = testEval(tt.input)
+
+		if evaluated.Inspect() != tt.legacy {
+			t.Errorf("wrong legacy result for %q. want=%s, got=%s",
+				tt.input, tt.legacy, evaluated.Inspect())
+		}
+	}
+}
+
 func TestLetStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -259,6 +923,88 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let a = 1; a = 2; a", 2},
+		{"let a = 1; a = 2", 2},
+		{"let a = 1; let b = 2; a = b = 3; a + b", 6},
+		{"let a = 10; a += 5; a", 15},
+		{"let a = 10; a -= 5; a", 5},
+		{"let a = 10; a *= 5; a", 50},
+		{"let a = 10; a /= 5; a", 2},
+		{`let s = "a"; s += "b"; s`, "ab"},
+		{"let a = 1; let f = fn() { a = 5 }; f(); a", 5},
+		{"let counter = fn() { let n = 0; fn() { n += 1 } }; let c = counter(); c(); c(); c()", 3},
+		{"let a = 1; let f = fn(a) { a = 5 }; f(0); a", 1},
+		{"b = 1", "assignment to undeclared identifier: b"},
+		{"let a = 1; a += true", "type mismatch: INTEGER + BOOLEAN"},
+		{"let a = 1; a /= 0", "division by zero: 1 / 0"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let a = [1, 2, 3]; a[1] = 5; a", "[1, 5, 3]"},
+		{"let a = [1, 2, 3]; a[1] = 5", 5},
+		{"let a = [1, 2, 3]; let b = a; b[0] = 9; a[0]", 9},
+		{"let a = [1, 2, 3]; a[2] += 10; a[2]", 13},
+		{"let a = [[1], [2]]; a[1][0] = 7; a", "[[1], [7]]"},
+		{"let a = [1]; let set = fn(xs) { xs[0] = 2 }; set(a); a[0]", 2},
+		{`let h = {"a": 1}; h["b"] = 2; h["b"]`, 2},
+		{`let h = {"a": 1}; h["a"] *= 3; h["a"]`, 3},
+		{`let h = {}; h[1] = "one"; h[true] = "yes"; h[1] + h[true]`, "oneyes"},
+		{"let a = [1, 2]; a[2] = 3", "index out of range: 2 (length 2)"},
+		{"let a = [1, 2]; a[-1] = 3", "index out of range: -1 (length 2)"},
+		{`let a = [1]; a["x"] = 1`, "array index must be INTEGER, got STRING"},
+		{"let h = {}; h[fn(x) { x }] = 1", "unusable as hash key: FUNCTION"},
+		{`let h = {}; h["a"] += 1`, `key not found: a`},
+		{`let s = "abc"; s[0] = "x"`, "index assignment not supported: STRING"},
+		{"let a = [1]; a[0] += true", "type mismatch: INTEGER + BOOLEAN"},
+		{"let a = [1, 2]; a[0] = a; a", "[[...], 2]"},
+		{`let h = {}; h["self"] = h; h`, "{self: {...}}"},
+		{"let a = [1]; a[0] = a; [a, a]", "[[[...]], [[...]]]"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const a = 5; a", 5},
+		{"const a = 5; let f = fn() { a * 2 }; f()", 10},
+		{"const a = 5; let f = fn() { let a = 1; a }; f()", 1},
+		{"const a = 5; let f = fn(a) { a = 2; a }; f(1)", 2},
+		{"const a = [1]; a[0] = 2; a", "[2]"},
+		{"let a = 1; const a = 2; a", 2},
+		{"const a = 5; let a = 6;", "cannot redeclare constant: a"},
+		{"const a = 5; const a = 6;", "cannot redeclare constant: a"},
+		{"const a = 5; a = 6;", "cannot assign to constant: a"},
+		{"const a = 5; a += 1;", "cannot assign to constant: a"},
+		{"const a = 5; let f = fn() { a = 1 }; f()", "cannot assign to constant: a"},
+		{"const x = 0; for (x in [1, 2]) { }; x", 0},
+		{"const x = 0; if (true) { let x = 1; x }", 1},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := `fn(x) { x + 2 };`
 
@@ -303,6 +1049,45 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
+func TestDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let f = fn(a, b = 10) { a + b }; f(1)", 11},
+		{"let f = fn(a, b = 10) { a + b }; f(1, 2)", 3},
+		{"let f = fn(a, b = a * 2) { a + b }; f(3)", 9},
+		{"let x = 5; let f = fn(a = x) { a }; let x = 7; f()", 7},
+		{"let f = fn(...rest) { len(rest) }; f()", 0},
+		{"let f = fn(a, ...rest) { len(rest) }; f(1, 2, 3)", 2},
+		{"let f = fn(a, ...rest) { rest[1] }; f(1, 2, 3)", 3},
+		{"let f = fn(a = 1, ...rest) { a }; f()", 1},
+		{"let f = fn(a, b = 2) { a + b }; f()", "wrong number of arguments to `f`: want=1..2, got=0"},
+		{"let f = fn(a, ...rest) { a }; f()", "wrong number of arguments to `f`: want>=1, got=0"},
+		{"let f = fn(a = -true) { a }; f()", "unknown operator: -BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned. got=%T (%+v)",
+					evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q",
+					expected, errObj.Message)
+			}
+		}
+	}
+}
+
 func TestClosures(t *testing.T) {
 	input := ` 
     let newAdder = fn(x) {
@@ -322,6 +1107,8 @@ func TestBuiltInFunction(t *testing.T) {
 		{`len("");`, 0},
 		{`len("four");`, 4},
 		{`len("hello world");`, 11},
+		{`len("héllo");`, 5},
+		{`len("日本語");`, 3},
 		{`len(1);`, "argument to `len` not supported, got INTEGER"},
 		{`len("one", "two");`, "wrong number of arguments. got=2, want=1"},
 		{`len([1, 2, 3])`, 3},
@@ -337,6 +1124,11 @@ func TestBuiltInFunction(t *testing.T) {
 		{`rest([])`, nil},
 		{`push([], 1)`, []int{1}},
 		{`push(1, 1)`, "argument to `push` must be ARRAY, got INTEGER"},
+		{`let h = {"a": 1}; delete(h, "a")`, 1},
+		{`let h = {"a": 1}; delete(h, "a"); h["a"]`, nil},
+		{`delete({}, "a")`, nil},
+		{`delete([1], 0)`, "argument to `delete` must be HASH, got ARRAY"},
+		{`delete({}, [])`, "unusable as hash key: ARRAY"},
 	}
 
 	for _, tt := range tests {
@@ -460,6 +1252,101 @@ func TestArrayIndexExpression(t *testing.T) {
 	}
 }
 
+func TestStringIndexExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc"[0]`, "a"},
+		{`"abc"[2]`, "c"},
+		{`"héllo"[1]`, "é"},
+		{`"héllo"[2]`, "l"},
+		{`let größe = "日本語"; größe[2]`, "語"},
+		{`"abc"[3]`, nil},
+		{`"abc"[-1]`, nil},
+		{`let s = ""; for (c in "añb") { s = c + s; }; s`, "bña"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestStringOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"a" == "a"`, "true"},
+		{`let a = "x"; let b = "x"; a == b`, "true"},
+		{`"a" != "b"`, "true"},
+		{`"abc" < "abd"`, "true"},
+		{`"b" > "abc"`, "true"},
+		{`"ab" <= "ab"`, "true"},
+		{`"" >= "a"`, "false"},
+		{`"a" == 1`, "false"},
+		{`"ab" * 3`, "ababab"},
+		{`2 * "-"`, "--"},
+		{`"ab" * 0`, ""},
+		{`"ab" * -1`, "negative repeat count: STRING * INTEGER"},
+		{`-1 * "ab"`, "negative repeat count: INTEGER * STRING"},
+		{`"ab" * -100000000000000000000`, "negative repeat count: STRING * BIGINT"},
+		{`"ab" * 1000000000000`, "string repetition too large: STRING * INTEGER"},
+		{`100000000000000000000 * "ab"`, "string repetition too large: BIGINT * STRING"},
+		{`"" * 100000000000000000000`, ""},
+		{`"ab" * 1.5`, "type mismatch: STRING * FLOAT"},
+		{`"a" - "b"`, "unknown operator: STRING - STRING"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSliceExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3][0:3]", "[1, 2, 3]"},
+		{"[1, 2, 3][2:10]", "[3]"},
+		{"[1, 2, 3][2:1]", "[]"},
+		{"[1, 2, 3][-5:1]", "[1]"},
+		{"let a = [1, 2, 3]; let b = a[0:2]; b[0] = 9; a", "[1, 2, 3]"},
+		{`"hello"[1:3]`, "el"},
+		{`"héllo"[0:2]`, "hé"},
+		{`"abc"[1:1]`, ""},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[2:]", "[2, 3, 4, 5]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[:2]", "[0, 1]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[:]", "[0, 1, 2, 3, 4, 5]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[-2:]", "[4, 5]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[:-2]", "[0, 1, 2, 3]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[-4:-1]", "[2, 3, 4]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[::2]", "[0, 2, 4]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[1::2]", "[1, 3, 5]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[::-1]", "[5, 4, 3, 2, 1, 0]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[4:1:-1]", "[4, 3, 2]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[-1:-4:-2]", "[5, 3]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[1:4:-1]", "[]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[-100:100:4]", "[0, 4]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[100::-5]", "[5, 0]"},
+		{"let a = [0, 1, 2, 3, 4, 5]; a[1:5:9223372036854775807]", "[1]"},
+		{"[][::-1]", "[]"},
+		{`"héllo"[::-1]`, "olléh"},
+		{`"héllo"[-3:]`, "llo"},
+		{`"abcdef"[::3]`, "ad"},
+		{"[1, 2][::0]", "slice step cannot be zero"},
+		{"[1, 2][0:1:true]", "slice bounds must be INTEGER, got BOOLEAN"},
+		{`"abc"["a":1]`, "slice bounds must be INTEGER, got STRING"},
+		{"5[0:1]", "slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestHashLiterals(t *testing.T) {
 	input := `let two = "two";
     {
@@ -559,6 +1446,28 @@ func testEval(input string) object.Object {
 	return Eval(program, env)
 }
 
+func testResult(t *testing.T, evaluated object.Object, expected interface{}) {
+	switch expected := expected.(type) {
+	case int:
+		testIntegerObject(t, evaluated, int64(expected))
+	case nil:
+		testNullObject(t, evaluated)
+	case string:
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q",
+					expected, errObj.Message)
+			}
+			return
+		}
+
+		if evaluated.Inspect() != expected {
+			t.Errorf("wrong result. expected=%q, got=%q",
+				expected, evaluated.Inspect())
+		}
+	}
+}
+
 func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	result, ok := obj.(*object.Integer)
 
@@ -575,6 +1484,23 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	return true
 }
 
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+	result, ok := obj.(*object.Float)
+
+	if !ok {
+		t.Errorf("Object is not Float. got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("Object has wrong value. got=%g, want=%g",
+			result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
 func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 	result, ok := obj.(*object.Boolean)
 
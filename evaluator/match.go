@@ -0,0 +1,125 @@
+package evaluator
+
+import (
+	"Mon/ast"
+	"Mon/object"
+)
+
+// evalMatchExpression evaluates the body of the first arm whose pattern
+// matches the subject, in a scope holding the names the pattern bound. It
+// returns NULL when no arm matches.
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	body, scope, err := matchArm(node, env)
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return NULL
+	}
+
+	return Eval(body, scope)
+}
+
+// matchArm finds the first arm of a match expression whose pattern matches
+// the subject. It returns the body of that arm and the scope to evaluate it
+// in, or a nil body when no arm matches.
+func matchArm(
+	node *ast.MatchExpression,
+	env *object.Environment,
+) (ast.Expression, *object.Environment, object.Object) {
+	subject := Eval(node.Subject, env)
+	if isError(subject) {
+		return nil, nil, subject
+	}
+
+	for _, arm := range node.Arms {
+		scope := object.NewEnclosedEnvironment(env)
+
+		if matchPattern(arm.Pattern, subject, scope) {
+			return arm.Body, scope, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// matchPattern reports whether value matches pattern, binding the names in
+// the pattern in scope as it goes.
+func matchPattern(pattern ast.Expression, value object.Object, scope *object.Environment) bool {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			scope.Set(pattern.Value, value)
+		}
+		return true
+
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, value, scope)
+
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, scope)
+
+	default:
+		literal := Eval(pattern, scope)
+		if isError(literal) {
+			return false
+		}
+
+		return evalInfixExpression("==", literal, value) == TRUE
+	}
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, value object.Object, scope *object.Environment) bool {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return false
+	}
+
+	if len(array.Elements) < len(pattern.Elements) {
+		return false
+	}
+
+	if pattern.Rest == nil && len(array.Elements) != len(pattern.Elements) {
+		return false
+	}
+
+	for i, element := range pattern.Elements {
+		if !matchPattern(element, array.Elements[i], scope) {
+			return false
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, len(array.Elements)-len(pattern.Elements))
+		copy(rest, array.Elements[len(pattern.Elements):])
+
+		return matchPattern(pattern.Rest, &object.Array{Elements: rest}, scope)
+	}
+
+	return true
+}
+
+func matchHashPattern(pattern *ast.HashPattern, value object.Object, scope *object.Environment) bool {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return false
+	}
+
+	for i, keyNode := range pattern.Keys {
+		key, ok := Eval(keyNode, scope).(object.Hashable)
+		if !ok {
+			return false
+		}
+
+		pair, ok := hash.Pairs[key.HashKey()]
+		if !ok {
+			return false
+		}
+
+		if !matchPattern(pattern.Values[i], pair.Value, scope) {
+			return false
+		}
+	}
+
+	return true
+}
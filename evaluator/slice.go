@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"Mon/ast"
+	"Mon/object"
+)
+
+// evalSliceExpression follows Python: a negative bound counts from the end,
+// bounds past either end are clamped, an omitted bound means the whole
+// sequence in the direction of the step, and a negative step walks
+// backwards.
+func evalSliceExpression(node *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var bounds [3]*int64
+
+	for i, exp := range []ast.Expression{node.Start, node.End, node.Step} {
+		if exp == nil {
+			continue
+		}
+
+		bound := Eval(exp, env)
+		if isError(bound) {
+			return bound
+		}
+
+		integer, ok := bound.(*object.Integer)
+		if !ok {
+			return newError("slice bounds must be INTEGER, got %s", bound.Type())
+		}
+
+		bounds[i] = &integer.Value
+	}
+
+	if bounds[2] != nil && *bounds[2] == 0 {
+		return newError("slice step cannot be zero")
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		indices := sliceIndices(bounds[0], bounds[1], bounds[2], len(left.Elements))
+
+		elements := make([]object.Object, len(indices))
+		for i, idx := range indices {
+			elements[i] = left.Elements[idx]
+		}
+
+		return &object.Array{Elements: elements}
+	case *object.String:
+		runes := []rune(left.Value)
+		indices := sliceIndices(bounds[0], bounds[1], bounds[2], len(runes))
+
+		sliced := make([]rune, len(indices))
+		for i, idx := range indices {
+			sliced[i] = runes[idx]
+		}
+
+		return &object.String{Value: string(sliced)}
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+}
+
+// sliceIndices returns the indices a slice with the given bounds, nil when
+// omitted, selects from a sequence of the given length. step must not be
+// zero.
+func sliceIndices(start, end, step *int64, length int) []int {
+	n := int64(length)
+
+	s := int64(1)
+	if step != nil {
+		s = *step
+	}
+
+	// the range an index is clamped to; -1 lets a backwards slice run past
+	// the first element
+	lower, upper := int64(0), n
+	if s < 0 {
+		lower, upper = -1, n-1
+	}
+
+	adjust := func(bound *int64, omitted int64) int64 {
+		if bound == nil {
+			return omitted
+		}
+
+		i := *bound
+		if i < 0 {
+			i += n
+		}
+
+		if i < lower {
+			return lower
+		}
+		if i > upper {
+			return upper
+		}
+		return i
+	}
+
+	var from, to int64
+	if s > 0 {
+		from, to = adjust(start, lower), adjust(end, upper)
+	} else {
+		from, to = adjust(start, upper), adjust(end, lower)
+	}
+
+	// count the indices up front, since stepping past the end could
+	// overflow with a huge step
+	var count uint64
+	if s > 0 && from < to {
+		count = uint64(to-from-1)/uint64(s) + 1
+	} else if s < 0 && from > to {
+		count = uint64(from-to-1)/uint64(-s) + 1
+	}
+
+	indices := make([]int, count)
+	for k := range indices {
+		indices[k] = int(from + int64(k)*s)
+	}
+
+	return indices
+}
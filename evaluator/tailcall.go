@@ -0,0 +1,114 @@
+package evaluator
+
+import (
+	"Mon/ast"
+	"Mon/object"
+	"Mon/token"
+)
+
+const tailCallObj = "TAIL_CALL"
+
+// tailCall is a call in tail position that has not been made yet. It is
+// handed back to applyFunction, which makes the call in a loop instead of
+// recursing, so deep tail recursion runs in constant Go stack space.
+type tailCall struct {
+	fn      object.Object
+	args    []object.Object
+	callPos token.Position
+}
+
+func (tc *tailCall) Type() object.ObjectType { return tailCallObj }
+func (tc *tailCall) Inspect() string         { return "tail call" }
+
+func evalTailCall(call *ast.CallExpression, env *object.Environment) object.Object {
+	function := Eval(call.Function, env)
+	if isError(function) {
+		return function
+	}
+
+	args := evalExpressions(call.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+
+	return &tailCall{fn: function, args: args, callPos: call.Function.Pos()}
+}
+
+// evalTailBlock evaluates a function body, or a branch of an if or match
+// inside one. A return of a call anywhere in it is a tail call, handed back
+// in a ReturnValue so that it ends the function like any other return. With
+// last set, the block's final expression is in tail position too.
+//
+// Only applyFunction and the tail evaluators below may see the tail calls
+// this returns; everywhere else a call is made through Eval.
+func evalTailBlock(block *ast.BlockStatement, env *object.Environment, last bool) object.Object {
+	var result object.Object
+
+	for i, statement := range block.Statements {
+		switch statement := statement.(type) {
+		case *ast.ReturnStatement:
+			call, ok := statement.ReturnValue.(*ast.CallExpression)
+			if !ok {
+				return Eval(statement, env)
+			}
+
+			tc := evalTailCall(call, env)
+			if isError(tc) {
+				return tc
+			}
+			return &object.ReturnValue{Value: tc}
+
+		case *ast.ExpressionStatement:
+			result = evalTailExpression(statement.Expression, env,
+				last && i == len(block.Statements)-1)
+
+		default:
+			result = Eval(statement, env)
+		}
+
+		if isReturnOrError(result) {
+			return result
+		}
+	}
+
+	return result
+}
+
+// evalTailExpression evaluates an expression statement of a function body.
+// With last set its value is the value of the function, so a call in it is
+// a tail call; otherwise only the returns in its branches can be.
+func evalTailExpression(exp ast.Expression, env *object.Environment, last bool) object.Object {
+	switch exp := exp.(type) {
+	case *ast.CallExpression:
+		if !last {
+			return Eval(exp, env)
+		}
+		return evalTailCall(exp, env)
+
+	case *ast.IfExpression:
+		condition := Eval(exp.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+
+		if isTruthy(condition) {
+			return evalTailBlock(exp.Consequence, blockEnv(env), last)
+		} else if exp.Alternative != nil {
+			return evalTailBlock(exp.Alternative, blockEnv(env), last)
+		}
+		return NULL
+
+	case *ast.MatchExpression:
+		body, scope, err := matchArm(exp, env)
+		if err != nil {
+			return err
+		}
+		if body == nil {
+			return NULL
+		}
+		return evalTailExpression(body, scope, last)
+
+	default:
+		return Eval(exp, env)
+	}
+}
@@ -2,59 +2,162 @@ package lexer
 
 import (
 	"Mon/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Lexer struct {
 	input        string
 	position     int
 	readPosition int
-	ch           byte
+	ch           rune // the character at position, decoded from UTF-8
+
+	filename string
+	line     int // line of ch
+	column   int // column of ch
+
+	keepComments bool
+	comments     []token.Comment // kept since the last token
+
+	templates []template // the string interpolations ch is inside of
+}
+
+// template is an interpolation in a string literal that has not been
+// closed yet.
+type template struct {
+	start int // position of the opening quote
+	depth int // braces opened inside the interpolation
 }
 
+// stringEnd is what ended the part of a string literal readString read.
+type stringEnd int
+
+const (
+	stringClosed stringEnd = iota
+	stringInterpolation
+	stringUnterminated
+)
+
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	return NewFile("", input)
+}
+
+// NewFile returns a lexer whose token positions name the given file.
+func NewFile(filename, input string) *Lexer {
+	l := &Lexer{input: input, filename: filename, line: 1}
 
 	l.readChar()
 
 	return l
 }
 
+// KeepComments makes the lexer attach the comments it skips to the token
+// that follows them, so that tools like formatters can reproduce them.
+func (l *Lexer) KeepComments() {
+	l.keepComments = true
+}
+
 func (l *Lexer) readChar() {
+	if l.readPosition > len(l.input) {
+		return // already at EOF
+	}
+
+	if l.ch == '\n' {
+		l.line += 1
+		l.column = 0
+	}
+
+	l.position = l.readPosition
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
+		l.readPosition += 1
 	} else {
-		l.ch = l.input[l.readPosition]
+		ch, size := utf8.DecodeRuneInString(l.input[l.readPosition:])
+		l.ch = ch
+		l.readPosition += size
 	}
 
-	l.position = l.readPosition
+	l.column += 1
+}
 
-	l.readPosition += 1
+func (l *Lexer) currPosition() token.Position {
+	return token.Position{Filename: l.filename, Line: l.line, Column: l.column}
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
-
 	l.skipWhitespace()
 
+	pos := l.currPosition()
+	tok := l.nextToken()
+	tok.Pos = pos
+	tok.End = l.currPosition()
+
+	tok.Comments = l.comments
+	l.comments = nil
+
+	return tok
+}
+
+func (l *Lexer) nextToken() token.Token {
+	var tok token.Token
+
 	switch l.ch {
 	case '=':
-		if l.peekChar() == '=' {
+		switch l.peekChar() {
+		case '=':
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(l.ch)}
-		} else {
+		case '>':
+			tok = l.readTwoCharToken(token.FAT_ARROW)
+		default:
 			tok = newToken(token.ASSIGN, l.ch)
 		}
 	case '+':
-		tok = newToken(token.PLUS, l.ch)
+		if l.peekChar() == '=' {
+			tok = l.readTwoCharToken(token.PLUS_ASSIGN)
+		} else {
+			tok = newToken(token.PLUS, l.ch)
+		}
 	case '(':
 		tok = newToken(token.LPAREN, l.ch)
 	case ')':
 		tok = newToken(token.RPAREN, l.ch)
 	case '{':
+		if len(l.templates) > 0 {
+			l.templates[len(l.templates)-1].depth++
+		}
 		tok = newToken(token.LBRACE, l.ch)
 	case '}':
-		tok = newToken(token.RBRACE, l.ch)
+		if len(l.templates) == 0 {
+			tok = newToken(token.RBRACE, l.ch)
+			break
+		}
+
+		top := &l.templates[len(l.templates)-1]
+		if top.depth > 0 {
+			top.depth--
+			tok = newToken(token.RBRACE, l.ch)
+			break
+		}
+
+		// the } closes an interpolation, so the string carries on
+		start := top.start
+		str, end := l.readString('"')
+
+		switch end {
+		case stringClosed:
+			l.templates = l.templates[:len(l.templates)-1]
+			tok = token.Token{Type: token.TEMPLATE_TAIL, Literal: str}
+		case stringInterpolation:
+			tok = token.Token{Type: token.TEMPLATE_MIDDLE, Literal: str}
+		default:
+			l.templates = l.templates[:len(l.templates)-1]
+			return token.Token{Type: token.ILLEGAL, Literal: l.input[start:]}
+		}
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
 	case ';':
@@ -68,35 +171,110 @@ func (l *Lexer) NextToken() token.Token {
 			tok = newToken(token.BANG, l.ch)
 		}
 	case '-':
-		tok = newToken(token.MINUS, l.ch)
+		if l.peekChar() == '=' {
+			tok = l.readTwoCharToken(token.MINUS_ASSIGN)
+		} else {
+			tok = newToken(token.MINUS, l.ch)
+		}
 	case '/':
-		tok = newToken(token.SLASH, l.ch)
+		switch l.peekChar() {
+		case '=':
+			tok = l.readTwoCharToken(token.SLASH_ASSIGN)
+		case '*':
+			// skipWhitespace leaves a block comment that is never closed
+			position := l.position
+			for l.ch != 0 {
+				l.readChar()
+			}
+			tok = token.Token{Type: token.ILLEGAL, Literal: l.input[position:]}
+			return tok
+		default:
+			tok = newToken(token.SLASH, l.ch)
+		}
 	case '*':
-		tok = newToken(token.ASTERISK, l.ch)
+		switch l.peekChar() {
+		case '*':
+			tok = l.readTwoCharToken(token.POWER)
+		case '=':
+			tok = l.readTwoCharToken(token.ASTERISK_ASSIGN)
+		default:
+			tok = newToken(token.ASTERISK, l.ch)
+		}
+	case '%':
+		tok = newToken(token.PERCENT, l.ch)
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		switch l.peekChar() {
+		case '=':
+			tok = l.readTwoCharToken(token.LT_EQ)
+		case '<':
+			tok = l.readTwoCharToken(token.LSHIFT)
+		default:
+			tok = newToken(token.LT, l.ch)
+		}
 	case '>':
-		tok = newToken(token.GT, l.ch)
+		switch l.peekChar() {
+		case '=':
+			tok = l.readTwoCharToken(token.GT_EQ)
+		case '>':
+			tok = l.readTwoCharToken(token.RSHIFT)
+		default:
+			tok = newToken(token.GT, l.ch)
+		}
+	case '&':
+		if l.peekChar() == '&' {
+			tok = l.readTwoCharToken(token.AND)
+		} else {
+			tok = newToken(token.AMPERSAND, l.ch)
+		}
+	case '|':
+		if l.peekChar() == '|' {
+			tok = l.readTwoCharToken(token.OR)
+		} else {
+			tok = newToken(token.PIPE, l.ch)
+		}
+	case '^':
+		tok = newToken(token.CARET, l.ch)
+	case '~':
+		tok = newToken(token.TILDE, l.ch)
 	case '[':
 		tok = newToken(token.LBRACKET, l.ch)
 	case ']':
 		tok = newToken(token.RBRACKET, l.ch)
 	case ':':
 		tok = newToken(token.COLON, l.ch)
+	case '.':
+		if strings.HasPrefix(l.input[l.position:], token.ELLIPSIS) {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: token.ELLIPSIS}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
-	case '"':
-		tok.Type = token.STRING
-		tok.Literal = l.readString()
+	case '"', '`':
+		position := l.position
+
+		str, end := l.readString(l.ch)
+
+		switch end {
+		case stringClosed:
+			tok = token.Token{Type: token.STRING, Literal: str}
+		case stringInterpolation:
+			l.templates = append(l.templates, template{start: position})
+			tok = token.Token{Type: token.TEMPLATE_HEAD, Literal: str}
+		default:
+			// the literal of an unterminated string is its source text
+			return token.Token{Type: token.ILLEGAL, Literal: l.input[position:]}
+		}
 	default:
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok.Literal, tok.Type = l.readNumber()
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
@@ -108,10 +286,18 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
+// readTwoCharToken consumes the next character as the second half of a two
+// character operator.
+func (l *Lexer) readTwoCharToken(tokenType token.TokenType) token.Token {
+	ch := l.ch
+	l.readChar()
+	return token.Token{Type: tokenType, Literal: string(ch) + string(l.ch)}
+}
+
 func (l *Lexer) readIdentifier() string {
 	position := l.position
 
@@ -122,47 +308,183 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
-func (l *Lexer) readNumber() string {
+// readNumber reads an integer, or a float when the digits are followed by
+// a fraction like `.5` or an exponent like `e-3`.
+func (l *Lexer) readNumber() (string, token.TokenType) {
 	position := l.position
+	tokenType := token.TokenType(token.INT)
 
-	for isDigit(l.ch) {
+	l.readDigits()
+
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
 		l.readChar()
+		l.readDigits()
 	}
 
-	return l.input[position:l.position]
+	if l.ch == 'e' || l.ch == 'E' {
+		exponent := l.input[l.readPosition:]
+		if len(exponent) > 0 && (exponent[0] == '+' || exponent[0] == '-') {
+			exponent = exponent[1:]
+		}
+
+		if len(exponent) > 0 && isDigit(rune(exponent[0])) {
+			tokenType = token.FLOAT
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				l.readChar()
+			}
+			l.readDigits()
+		}
+	}
+
+	return l.input[position:l.position], tokenType
+}
+
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) {
+		l.readChar()
+	}
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+// skipWhitespace skips whitespace and comments, other than a block comment
+// that is not closed, which nextToken turns into an ILLEGAL token.
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\n' || l.ch == '\t' || l.ch == '\r' {
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\n' || l.ch == '\t' || l.ch == '\r':
+			l.readChar()
+		case l.ch == '/' && l.peekChar() == '/':
+			l.skipComment("\n")
+		case l.ch == '/' && l.peekChar() == '*':
+			if !strings.Contains(l.input[l.position+2:], "*/") {
+				return
+			}
+			l.skipComment("*/")
+		default:
+			return
+		}
+	}
+}
+
+// skipComment skips a comment up to and including end, or up to the end of
+// the input. A line comment does not include its newline.
+func (l *Lexer) skipComment(end string) {
+	position := l.position
+	pos := l.currPosition()
+
+	for l.ch != 0 && !strings.HasPrefix(l.input[l.position:], end) {
 		l.readChar()
 	}
+
+	if end != "\n" {
+		for range end {
+			l.readChar()
+		}
+	}
+
+	if l.keepComments {
+		l.comments = append(l.comments, token.Comment{
+			Text: l.input[position:l.position],
+			Pos:  pos,
+			End:  l.currPosition(),
+		})
+	}
 }
 
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
-	} else {
-		return l.input[l.readPosition]
 	}
+
+	ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return ch
 }
 
-func (l *Lexer) readString() string {
-	position := l.position + 1
+// readString reads a string literal after ch up to its closing quote, or
+// up to the { of an interpolation, which it leaves in ch. A string in double
+// quotes may contain escape sequences and interpolations; a raw string, in
+// backquotes, is taken as written.
+func (l *Lexer) readString(quote rune) (string, stringEnd) {
+	var out strings.Builder
 
 	for {
 		l.readChar()
 
-		if l.ch == '"' || l.ch == 0 {
-			break
+		switch {
+		case l.ch == 0:
+			return "", stringUnterminated
+		case l.ch == quote:
+			return out.String(), stringClosed
+		case l.ch == '$' && quote == '"' && l.peekChar() == '{':
+			l.readChar()
+			return out.String(), stringInterpolation
+		case l.ch == '\\' && quote == '"':
+			l.readChar()
+			if l.ch == 0 {
+				return "", stringUnterminated
+			}
+			l.readEscape(&out)
+		default:
+			out.WriteRune(l.ch)
 		}
 	}
-	return l.input[position:l.position]
+}
+
+// readEscape writes the character escaped by the backslash before ch. An
+// unknown escape sequence is kept as written.
+func (l *Lexer) readEscape(out *strings.Builder) {
+	switch l.ch {
+	case 'n':
+		out.WriteByte('\n')
+	case 't':
+		out.WriteByte('\t')
+	case 'r':
+		out.WriteByte('\r')
+	case '\\', '"', '$':
+		out.WriteRune(l.ch)
+	case 'u':
+		if r, ok := l.readUnicodeEscape(); ok {
+			out.WriteRune(r)
+			return
+		}
+		out.WriteString("\\u")
+	default:
+		out.WriteByte('\\')
+		out.WriteRune(l.ch)
+	}
+}
+
+// readUnicodeEscape reads the {hex} of a \u{hex} escape, leaving the closing
+// brace in ch. It reads nothing if the escape is not a valid code point.
+func (l *Lexer) readUnicodeEscape() (rune, bool) {
+	if l.peekChar() != '{' {
+		return 0, false
+	}
+
+	rest := l.input[l.readPosition+1:]
+
+	end := strings.IndexByte(rest, '}')
+	if end < 1 || end > 6 {
+		return 0, false
+	}
+
+	code, err := strconv.ParseUint(rest[:end], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return 0, false
+	}
+
+	for i := 0; i < end+2; i++ {
+		l.readChar()
+	}
+
+	return rune(code), true
 }
@@ -14,7 +14,7 @@ func TestNextToken(t *testing.T) {
         };
         
         let result = add(five, ten);
-        !-/*5;
+        !-/ *5;
         5 < 10 > 5;
         
         if (5 < 10) {
@@ -29,6 +29,14 @@ func TestNextToken(t *testing.T) {
         "foo bar"
         [1, 2];
         { "foo": "bar" }
+        fn(a = 1, ...rest)
+        3.14 1e3 2.5E-2 1.
+        a && b || c
+        <= >= % ** & | ^ ~ << >>
+        while for in const match => // a comment
+        /* a block
+           comment */ /**/ 1 / /* inline */ 2 // the end
+        x = 1; x += 1 -= 2 *= 3 /= 4
     `
 
 	tests := []struct {
@@ -132,6 +140,63 @@ func TestNextToken(t *testing.T) {
 		{token.COLON, ":"},
 		{token.STRING, "bar"},
 		{token.RBRACE, "}"},
+
+		{token.FUNCTION, "fn"},
+		{token.LPAREN, "("},
+		{token.IDENT, "a"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RPAREN, ")"},
+
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "1e3"},
+		{token.FLOAT, "2.5E-2"},
+		{token.INT, "1"},
+		{token.ILLEGAL, "."},
+
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.OR, "||"},
+		{token.IDENT, "c"},
+
+		{token.LT_EQ, "<="},
+		{token.GT_EQ, ">="},
+		{token.PERCENT, "%"},
+		{token.POWER, "**"},
+		{token.AMPERSAND, "&"},
+		{token.PIPE, "|"},
+		{token.CARET, "^"},
+		{token.TILDE, "~"},
+		{token.LSHIFT, "<<"},
+		{token.RSHIFT, ">>"},
+
+		{token.WHILE, "while"},
+		{token.FOR, "for"},
+		{token.IN, "in"},
+		{token.CONST, "const"},
+		{token.MATCH, "match"},
+		{token.FAT_ARROW, "=>"},
+		{token.INT, "1"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "1"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "2"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "3"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "4"},
 		{token.EOF, ""},
 	}
 
@@ -150,3 +215,263 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := `let x = 5;
+  x == 10;
+"hi"`
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedLine   int
+		expectedColumn int
+		expectedEndCol int
+	}{
+		{token.LET, 1, 1, 4},
+		{token.IDENT, 1, 5, 6},
+		{token.ASSIGN, 1, 7, 8},
+		{token.INT, 1, 9, 10},
+		{token.SEMICOLON, 1, 10, 11},
+		{token.IDENT, 2, 3, 4},
+		{token.EQ, 2, 5, 7},
+		{token.INT, 2, 8, 10},
+		{token.SEMICOLON, 2, 10, 11},
+		{token.STRING, 3, 1, 5},
+		{token.EOF, 3, 5, 5},
+	}
+
+	l := NewFile("test.mon", input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Pos.Filename != "test.mon" {
+			t.Fatalf("tests[%d] - filename wrong. got=%q", i, tok.Pos.Filename)
+		}
+		if tok.Pos.Line != tt.expectedLine || tok.Pos.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Pos.Line, tok.Pos.Column)
+		}
+		if tok.End.Line != tt.expectedLine || tok.End.Column != tt.expectedEndCol {
+			t.Fatalf("tests[%d] - end position wrong. expected=%d:%d got=%d:%d",
+				i, tt.expectedLine, tt.expectedEndCol, tok.End.Line, tok.End.Column)
+		}
+	}
+}
+
+func TestKeepComments(t *testing.T) {
+	input := `// header
+let x = 1; /* one */ /* two
+*/ x // trailing`
+
+	tests := []struct {
+		expectedType     token.TokenType
+		expectedComments []string
+	}{
+		{token.LET, []string{"// header"}},
+		{token.IDENT, nil},
+		{token.ASSIGN, nil},
+		{token.INT, nil},
+		{token.SEMICOLON, nil},
+		{token.IDENT, []string{"/* one */", "/* two\n*/"}},
+		{token.EOF, []string{"// trailing"}},
+	}
+
+	l := New(input)
+	l.KeepComments()
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if len(tok.Comments) != len(tt.expectedComments) {
+			t.Fatalf("tests[%d] - wrong number of comments. expected=%d got=%d",
+				i, len(tt.expectedComments), len(tok.Comments))
+		}
+
+		for j, comment := range tt.expectedComments {
+			if tok.Comments[j].Text != comment {
+				t.Errorf("tests[%d] - comment %d wrong. expected=%q got=%q",
+					i, j, comment, tok.Comments[j].Text)
+			}
+		}
+	}
+}
+
+func TestCommentPositions(t *testing.T) {
+	l := New("1 /* a\nb */ 2")
+	l.KeepComments()
+
+	l.NextToken()
+	tok := l.NextToken()
+
+	comment := tok.Comments[0]
+
+	if comment.Pos.String() != "1:3" || comment.End.String() != "2:5" {
+		t.Errorf("comment has wrong span, got=%s-%s", comment.Pos, comment.End)
+	}
+
+	if tok.Pos.String() != "2:6" {
+		t.Errorf("token after comment has wrong position, got=%s", tok.Pos)
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	l := New("1 /* never closed")
+
+	l.NextToken()
+	tok := l.NextToken()
+
+	if tok.Type != token.ILLEGAL || tok.Literal != "/* never closed" {
+		t.Fatalf("expected ILLEGAL %q, got=%s %q", "/* never closed", tok.Type, tok.Literal)
+	}
+
+	if tok := l.NextToken(); tok.Type != token.EOF {
+		t.Fatalf("expected EOF after the comment, got=%s", tok.Type)
+	}
+}
+
+func TestStringLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{`"plain"`, token.STRING, "plain"},
+		{`"a\nb\tc\r"`, token.STRING, "a\nb\tc\r"},
+		{`"say \"hi\""`, token.STRING, `say "hi"`},
+		{`"back\\slash"`, token.STRING, `back\slash`},
+		{`"\u{48}\u{e9}\u{1F600}"`, token.STRING, "Hé😀"},
+		{`"\u{110000} \u{} \u41 \q"`, token.STRING, `\u{110000} \u{} \u41 \q`},
+		{"\"two\nlines\"", token.STRING, "two\nlines"},
+		{"`raw \\n \"quoted\"\nnext`", token.STRING, "raw \\n \"quoted\"\nnext"},
+		{`"never closed`, token.ILLEGAL, `"never closed`},
+		{`"escaped quote\"`, token.ILLEGAL, `"escaped quote\"`},
+		{`"trailing backslash\`, token.ILLEGAL, `"trailing backslash\`},
+		{"`raw never closed", token.ILLEGAL, "`raw never closed"},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Errorf("wrong token type for %q. expected=%q got=%q",
+				tt.input, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("wrong literal for %q. expected=%q got=%q",
+				tt.input, tt.expectedLiteral, tok.Literal)
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("expected EOF after %q, got=%s %q", tt.input, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	input := `"a ${x} b ${ {"k": "${y}"}["k"] } c" "${z}" "\${not} $x"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.TEMPLATE_HEAD, "a "},
+		{token.IDENT, "x"},
+		{token.TEMPLATE_MIDDLE, " b "},
+		{token.LBRACE, "{"},
+		{token.STRING, "k"},
+		{token.COLON, ":"},
+		{token.TEMPLATE_HEAD, ""},
+		{token.IDENT, "y"},
+		{token.TEMPLATE_TAIL, ""},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.STRING, "k"},
+		{token.RBRACKET, "]"},
+		{token.TEMPLATE_TAIL, " c"},
+		{token.TEMPLATE_HEAD, ""},
+		{token.IDENT, "z"},
+		{token.TEMPLATE_TAIL, ""},
+		{token.STRING, "${not} $x"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestUnterminatedInterpolatedString(t *testing.T) {
+	l := New(`"a ${x} b`)
+
+	for _, expected := range []token.TokenType{token.TEMPLATE_HEAD, token.IDENT} {
+		if tok := l.NextToken(); tok.Type != expected {
+			t.Fatalf("expected %s, got=%s", expected, tok.Type)
+		}
+	}
+
+	tok := l.NextToken()
+
+	if tok.Type != token.ILLEGAL || tok.Literal != `"a ${x} b` {
+		t.Fatalf("expected ILLEGAL %q, got=%s %q", `"a ${x} b`, tok.Type, tok.Literal)
+	}
+}
+
+func TestUnicode(t *testing.T) {
+	input := `let größe = "héllo"; 名前 € x`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedColumn  int
+	}{
+		{token.LET, "let", 1},
+		{token.IDENT, "größe", 5},
+		{token.ASSIGN, "=", 11},
+		{token.STRING, "héllo", 13},
+		{token.SEMICOLON, ";", 20},
+		{token.IDENT, "名前", 22},
+		{token.ILLEGAL, "€", 25},
+		{token.IDENT, "x", 27},
+		{token.EOF, "", 28},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Pos.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - column wrong. expected=%d got=%d",
+				i, tt.expectedColumn, tok.Pos.Column)
+		}
+	}
+}
@@ -1,13 +1,51 @@
 package main
 
 import (
+	"Mon/evaluator"
+	"Mon/lexer"
+	"Mon/object"
+	"Mon/parser"
 	"Mon/repl"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
+	"strings"
 )
 
+const usage = `usage: mon [-e program | file]
+
+With no arguments and an interactive terminal, mon starts the REPL.
+A file argument of "-", or input piped into stdin, runs that program.
+`
+
 func main() {
+	expr := flag.String("e", "", "evaluate `program` and print its result")
+	flag.BoolVar(&evaluator.CheckedArithmetic, "checked", false,
+		"report integer overflow as an error")
+	flag.BoolVar(&evaluator.LegacyScoping, "legacy-scope", false,
+		"run blocks in the enclosing scope, so a let inside them is visible after them")
+
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	switch {
+	case isFlagSet("e") && flag.NArg() > 0, flag.NArg() > 1:
+		flag.Usage()
+		os.Exit(2)
+	case isFlagSet("e"):
+		os.Exit(run("<expr>", *expr, os.Stdout, os.Stderr))
+	case flag.NArg() == 1:
+		os.Exit(runFile(flag.Arg(0)))
+	case !isTerminal(os.Stdin):
+		os.Exit(runFile("-"))
+	}
+
 	user, err := user.Current()
 
 	if err != nil {
@@ -19,3 +57,100 @@ func main() {
 
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+func runFile(path string) int {
+	var (
+		src []byte
+		err error
+	)
+
+	name := path
+
+	if path == "-" {
+		name = "<stdin>"
+		src, err = io.ReadAll(os.Stdin)
+	} else {
+		src, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mon: %s\n", err)
+		return 1
+	}
+
+	return run(name, string(src), nil, os.Stderr)
+}
+
+// run evaluates a whole program and returns the process exit status. When
+// out is not nil the value of the program is written to it. Diagnostics and
+// runtime errors go to errOut.
+func run(name, src string, out, errOut io.Writer) int {
+	l := lexer.NewFile(name, stripShebang(src))
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		for _, d := range p.Diagnostics() {
+			fmt.Fprintln(errOut, d)
+			if d.Hint != "" {
+				fmt.Fprintf(errOut, "\thint: %s\n", d.Hint)
+			}
+		}
+		return 1
+	}
+
+	env := object.NewEnvironment()
+	evaluated := evaluator.Eval(program, env)
+
+	if errObj, ok := evaluated.(*object.Error); ok {
+		fmt.Fprintln(errOut, errObj.Inspect())
+		fmt.Fprint(errOut, errObj.StackTrace())
+		return 1
+	}
+
+	if out != nil && evaluated != nil {
+		io.WriteString(out, evaluated.Inspect())
+		io.WriteString(out, "\n")
+	}
+
+	return 0
+}
+
+// stripShebang blanks out a leading "#!" line so scripts can be made
+// executable. The newline is kept so that line numbers stay the same.
+func stripShebang(src string) string {
+	if !strings.HasPrefix(src, "#!") {
+		return src
+	}
+
+	if idx := strings.IndexByte(src, '\n'); idx >= 0 {
+		return src[idx:]
+	}
+
+	return ""
+}
+
+// isFlagSet reports whether the named flag was given on the command line,
+// even with an empty value.
+func isFlagSet(name string) bool {
+	set := false
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+
+	if err != nil {
+		return true
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
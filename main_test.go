@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		input  string
+		status int
+		stdout string
+		stderr string
+	}{
+		{"1 + 2", 0, "3\n", ""},
+		{"let x = 1;", 0, "", ""},
+		{"", 0, "", ""},
+		{"let x 5", 1, "",
+			"<expr>:1:7: expected next token to be =, got INT instead\n"},
+		{"1 +", 1, "",
+			"<expr>:1:4: no prefix parse function for EOF found\n" +
+				"\thint: the input ended where an expression was expected\n"},
+		{"let f = fn() { 1 + true }; f()", 1, "",
+			"ERROR: <expr>:1:18: type mismatch: INTEGER + BOOLEAN\n" +
+				"\tin f, called at <expr>:1:28\n"},
+		{"#!/usr/bin/env mon\nlen(1)", 1, "",
+			"ERROR: <expr>:2:1: argument to `len` not supported, got INTEGER\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+
+		status := run("<expr>", tt.input, &stdout, &stderr)
+
+		if status != tt.status {
+			t.Errorf("wrong exit status for %q. want=%d, got=%d",
+				tt.input, tt.status, status)
+		}
+
+		if stdout.String() != tt.stdout {
+			t.Errorf("wrong stdout for %q. want=%q, got=%q",
+				tt.input, tt.stdout, stdout.String())
+		}
+
+		if stderr.String() != tt.stderr {
+			t.Errorf("wrong stderr for %q. want=%q, got=%q",
+				tt.input, tt.stderr, stderr.String())
+		}
+	}
+}
+
+func TestRunWithoutOutput(t *testing.T) {
+	var stderr bytes.Buffer
+
+	if status := run("<stdin>", "1 + 2", nil, &stderr); status != 0 {
+		t.Errorf("wrong exit status. want=0, got=%d", status)
+	}
+
+	if stderr.Len() != 0 {
+		t.Errorf("unexpected stderr. got=%q", stderr.String())
+	}
+}
+
+func TestStripShebang(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"#!/usr/bin/env mon\nlet x = 1;", "\nlet x = 1;"},
+		{"#!/usr/bin/env mon", ""},
+		{"let x = 1;\n#!", "let x = 1;\n#!"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := stripShebang(tt.input); got != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q",
+				tt.input, tt.expected, got)
+		}
+	}
+}
@@ -9,12 +9,14 @@ func NewEnclosedEnvironment(outer *Environment) *Environment {
 
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	c := make(map[string]bool)
+	return &Environment{store: s, constants: c, outer: nil}
 }
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store     map[string]Object
+	constants map[string]bool // names bound by const, which are read-only
+	outer     *Environment
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -29,3 +31,31 @@ func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
+
+// SetConst binds name like Set and marks the binding read-only.
+func (e *Environment) SetConst(name string, val Object) Object {
+	e.constants[name] = true
+	return e.Set(name, val)
+}
+
+// IsConst reports whether name is bound by const in e itself, ignoring
+// outer environments, where the name may be shadowed freely.
+func (e *Environment) IsConst(name string) bool {
+	return e.constants[name]
+}
+
+// Assign updates name in the innermost environment that declares it. It
+// reports false if no environment in the chain does, or if the binding it
+// finds is read-only.
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			if env.constants[name] {
+				return nil, false
+			}
+			env.store[name] = val
+			return val, true
+		}
+	}
+	return nil, false
+}
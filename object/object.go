@@ -2,14 +2,20 @@ package object
 
 import (
 	"Mon/ast"
+	"Mon/token"
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"math/big"
+	"strconv"
 	"strings"
 )
 
 const (
 	INTEGER_OBJ      = "INTEGER"
+	BIGINT_OBJ       = "BIGINT"
+	FLOAT_OBJ        = "FLOAT"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	STRING_OBJ       = "STRING"
 	NULL_OBJ         = "NULL"
@@ -36,6 +42,16 @@ type Integer struct {
 	Value int64
 }
 
+// BigInt is an integer too large for Integer. Arithmetic keeps values that
+// fit in an int64 as Integer, so equal numbers always have the same type.
+type BigInt struct {
+	Value *big.Int
+}
+
+type Float struct {
+	Value float64
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -52,10 +68,25 @@ type ReturnValue struct {
 
 type Error struct {
 	Message string
+	Pos     token.Position // where the error was raised, if known
+	Stack   []Frame        // calls the error passed through, innermost first
+}
+
+// Frame is a single function call on the evaluator's call stack.
+type Frame struct {
+	Function string
+	CallPos  token.Position
+
+	// Elided counts the tail calls made after this one that were left out
+	// of the stack to keep deep tail recursion from growing it.
+	Elided int
 }
 
 type Function struct {
+	Name       string
 	Parameters []*ast.Identifier
+	Defaults   []ast.Expression
+	Rest       *ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -88,6 +119,23 @@ type Hash struct {
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 
+// big int
+func (bi *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (bi *BigInt) Inspect() string  { return bi.Value.String() }
+
+// float
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+
+	// keep floats recognisable when they hold a whole number
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+
+	return s
+}
+
 // boolean
 func (b *Boolean) Type() ObjectType { return BOOLEAN_OBJ }
 func (b *Boolean) Inspect() string  { return fmt.Sprintf("%t", b.Value) }
@@ -106,7 +154,42 @@ func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
 // error
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
-func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
+func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return "ERROR: " + e.Pos.String() + ": " + e.Message
+	}
+	return "ERROR: " + e.Message
+}
+
+// at most this many frames are printed from each end of a long stack trace
+const maxTraceFrames = 10
+
+// StackTrace renders the call stack of the error, one frame per line.
+func (e *Error) StackTrace() string {
+	var out bytes.Buffer
+
+	for i, frame := range e.Stack {
+		if len(e.Stack) > 2*maxTraceFrames && i == maxTraceFrames {
+			out.WriteString(fmt.Sprintf("\t... %d more calls ...\n",
+				len(e.Stack)-2*maxTraceFrames))
+		}
+
+		if len(e.Stack) > 2*maxTraceFrames &&
+			i >= maxTraceFrames && i < len(e.Stack)-maxTraceFrames {
+			continue
+		}
+
+		if frame.Elided > 0 {
+			out.WriteString(fmt.Sprintf("\t... %d more tail calls ...\n",
+				frame.Elided))
+		}
+
+		out.WriteString(fmt.Sprintf("\tin %s, called at %s\n",
+			frame.Function, frame.CallPos))
+	}
+
+	return out.String()
+}
 
 // function
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -114,8 +197,16 @@ func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
+	for i, p := range f.Parameters {
+		if i < len(f.Defaults) && f.Defaults[i] != nil {
+			params = append(params, p.String()+" = "+f.Defaults[i].String())
+		} else {
+			params = append(params, p.String())
+		}
+	}
+
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
 	}
 
 	out.WriteString("fn")
@@ -134,12 +225,14 @@ func (b *Builtin) Inspect() string  { return "builtin function" }
 
 // array
 func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
-func (ao *Array) Inspect() string {
+func (ao *Array) Inspect() string  { return inspect(ao, map[Object]bool{}) }
+
+func (ao *Array) inspect(seen map[Object]bool) string {
 	var out bytes.Buffer
 
 	elements := []string{}
 	for _, el := range ao.Elements {
-		elements = append(elements, el.Inspect())
+		elements = append(elements, inspect(el, seen))
 	}
 
 	out.WriteString("[")
@@ -166,6 +259,32 @@ func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+func (bi *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	if bi.Value.Sign() < 0 {
+		h.Write([]byte{'-'})
+	}
+	h.Write(bi.Value.Bytes())
+
+	return HashKey{Type: bi.Type(), Value: h.Sum64()}
+}
+
+// HashKey of a float that holds a whole number is the key of that integer,
+// so that keys equal by == find the same entry: 1.0 looks up 1, and -0.0
+// looks up 0.0.
+func (f *Float) HashKey() HashKey {
+	if f.Value == math.Trunc(f.Value) && !math.IsInf(f.Value, 0) {
+		if f.Value >= math.MinInt64 && f.Value < math.MaxInt64 {
+			return (&Integer{Value: int64(f.Value)}).HashKey()
+		}
+
+		n, _ := big.NewFloat(f.Value).Int(nil)
+		return (&BigInt{Value: n}).HashKey()
+	}
+
+	return HashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
+
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
 	h.Write([]byte(s.Value))
@@ -175,13 +294,15 @@ func (s *String) HashKey() HashKey {
 
 // hash
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
-func (h *Hash) Inspect() string {
+func (h *Hash) Inspect() string  { return inspect(h, map[Object]bool{}) }
+
+func (h *Hash) inspect(seen map[Object]bool) string {
 	var out bytes.Buffer
 
 	pairs := []string{}
 	for _, pair := range h.Pairs {
 		pairs = append(pairs, fmt.Sprintf("%s: %s",
-			pair.Key.Inspect(), pair.Value.Inspect()))
+			pair.Key.Inspect(), inspect(pair.Value, seen)))
 	}
 
 	out.WriteString("{")
@@ -190,3 +311,29 @@ func (h *Hash) Inspect() string {
 
 	return out.String()
 }
+
+// inspect renders obj like Inspect. Index assignment can put an array or a
+// hash inside itself, so one that is already being rendered further out is
+// printed as [...] or {...} instead of recursing forever.
+func inspect(obj Object, seen map[Object]bool) string {
+	switch obj := obj.(type) {
+	case *Array:
+		if seen[obj] {
+			return "[...]"
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		return obj.inspect(seen)
+	case *Hash:
+		if seen[obj] {
+			return "{...}"
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		return obj.inspect(seen)
+	default:
+		return obj.Inspect()
+	}
+}
@@ -1,6 +1,10 @@
 package object
 
-import "testing"
+import (
+	"math"
+	"math/big"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello world"}
@@ -21,3 +25,64 @@ func TestStringHashKey(t *testing.T) {
 	}
 
 }
+
+func TestBigIntHashKey(t *testing.T) {
+	big1 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 100)}
+	big2 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 100)}
+	neg := &BigInt{Value: new(big.Int).Neg(big1.Value)}
+
+	if big1.HashKey() != big2.HashKey() {
+		t.Errorf("big ints with same value have different hash keys")
+	}
+
+	if big1.HashKey() == neg.HashKey() {
+		t.Errorf("big ints with different sign have same hash keys")
+	}
+}
+
+func TestFloatHashKey(t *testing.T) {
+	tests := []struct {
+		float Hashable
+		other Hashable
+		same  bool
+	}{
+		{&Float{Value: 0}, &Float{Value: math.Copysign(0, -1)}, true},
+		{&Float{Value: 1}, &Integer{Value: 1}, true},
+		{&Float{Value: -3}, &Integer{Value: -3}, true},
+		{&Float{Value: 1e20}, &BigInt{Value: new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)}, true},
+		{&Float{Value: 1.5}, &Float{Value: 1.5}, true},
+		{&Float{Value: 1.5}, &Integer{Value: 1}, false},
+		{&Float{Value: math.Inf(1)}, &Float{Value: math.Inf(-1)}, false},
+	}
+
+	for _, tt := range tests {
+		if (tt.float.HashKey() == tt.other.HashKey()) != tt.same {
+			t.Errorf("hash keys of %s and %s: want same=%t",
+				tt.float.(Object).Inspect(), tt.other.(Object).Inspect(), tt.same)
+		}
+	}
+}
+
+func TestInspectCycles(t *testing.T) {
+	array := &Array{Elements: []Object{&Integer{Value: 1}}}
+	array.Elements = append(array.Elements, array)
+
+	if array.Inspect() != "[1, [...]]" {
+		t.Errorf("array containing itself has wrong Inspect. got=%q", array.Inspect())
+	}
+
+	hash := &Hash{Pairs: map[HashKey]HashPair{}}
+	key := &String{Value: "self"}
+	hash.Pairs[key.HashKey()] = HashPair{Key: key, Value: &Array{Elements: []Object{hash}}}
+
+	if hash.Inspect() != "{self: [{...}]}" {
+		t.Errorf("hash containing itself has wrong Inspect. got=%q", hash.Inspect())
+	}
+
+	shared := &Array{}
+	outer := &Array{Elements: []Object{shared, shared}}
+
+	if outer.Inspect() != "[[], []]" {
+		t.Errorf("array holding the same array twice has wrong Inspect. got=%q", outer.Inspect())
+	}
+}
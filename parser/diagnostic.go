@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"Mon/token"
+	"fmt"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+// diagnostic codes, stable across releases so tools can match on them
+const (
+	CodeUnexpectedToken = "P001"
+	CodeNoPrefixParseFn = "P002"
+	CodeInvalidInteger  = "P003"
+	CodeInvalidParams   = "P004"
+	CodeInvalidFloat    = "P005"
+	CodeInvalidAssign   = "P006"
+	CodeMissingInit     = "P007"
+	CodeInvalidPattern  = "P008"
+	CodeIllegalToken    = "P009"
+)
+
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+
+	Pos token.Position
+	End token.Position
+
+	Expected []token.TokenType // tokens that would have been accepted, if known
+	Found    token.TokenType
+	Hint     string
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// String renders the diagnostic in the "file:line:col: message" form
+// returned by Parser.Errors.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
@@ -5,32 +5,58 @@ import (
 	"Mon/lexer"
 	"Mon/token"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // = or +=
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
+	BITWISE_OR  // |
+	BITWISE_XOR // ^
+	BITWISE_AND // &
 	EQUALS      // ==
 	LESSGREATER // < or >
+	SHIFT       // << or >>
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // !X or -X
+	POWER       // X ** Y
 	CALL        // myFunction(X)
 	INDEX       // array[index]
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.OR:              LOGICAL_OR,
+	token.AND:             LOGICAL_AND,
+	token.PIPE:            BITWISE_OR,
+	token.CARET:           BITWISE_XOR,
+	token.AMPERSAND:       BITWISE_AND,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.LT_EQ:           LESSGREATER,
+	token.GT_EQ:           LESSGREATER,
+	token.LSHIFT:          SHIFT,
+	token.RSHIFT:          SHIFT,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         PRODUCT,
+	token.POWER:           POWER,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
 }
 
 type (
@@ -39,8 +65,13 @@ type (
 )
 
 type Parser struct {
-	l      *lexer.Lexer
-	errors []string
+	l           *lexer.Lexer
+	diagnostics []Diagnostic
+
+	// panicking is set by the first error in a statement and cleared once
+	// the parser has resynchronized, so follow-on errors are not reported.
+	panicking bool
+	depth     int // number of open braces up to and including currToken
 
 	currToken token.Token
 	peekToken token.Token
@@ -51,26 +82,31 @@ type Parser struct {
 
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
-		l:      l,
-		errors: []string{},
+		l:           l,
+		diagnostics: []Diagnostic{},
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
 
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
 
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.TEMPLATE_HEAD, p.parseInterpolatedString)
 
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
@@ -85,6 +121,23 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
+	p.registerInfix(token.POWER, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.CARET, p.parseInfixExpression)
+	p.registerInfix(token.LSHIFT, p.parseInfixExpression)
+	p.registerInfix(token.RSHIFT, p.parseInfixExpression)
+
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
 
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 
@@ -99,6 +152,13 @@ func New(l *lexer.Lexer) *Parser {
 func (p *Parser) nextToken() {
 	p.currToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	switch p.currToken.Type {
+	case token.LBRACE:
+		p.depth++
+	case token.RBRACE:
+		p.depth--
+	}
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
@@ -108,6 +168,17 @@ func (p *Parser) ParseProgram() *ast.Program {
 	for !p.currTokenIs(token.EOF) {
 		stmt := p.parseStatement()
 
+		if p.panicking {
+			p.synchronize(0)
+
+			// a stray closing brace at the top level, skip it
+			if p.depth < 0 {
+				p.depth = 0
+				p.nextToken()
+			}
+			continue
+		}
+
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
@@ -119,16 +190,64 @@ func (p *Parser) ParseProgram() *ast.Program {
 }
 
 func (p *Parser) parseStatement() ast.Statement {
+	var stmt ast.Statement
 
 	switch p.currToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		if s := p.parseLetStatement(); s != nil {
+			stmt = s
+		}
+	case token.CONST:
+		if s := p.parseConstStatement(); s != nil {
+			stmt = s
+		}
 	case token.RETURN:
-		return p.parseReturnStatement()
+		if s := p.parseReturnStatement(); s != nil {
+			stmt = s
+		}
+	case token.WHILE:
+		if s := p.parseWhileStatement(); s != nil {
+			stmt = s
+		}
+	case token.FOR:
+		if s := p.parseForStatement(); s != nil {
+			stmt = s
+		}
 	default:
-		return p.parseExpressionStatement()
+		if s := p.parseExpressionStatement(); s != nil {
+			stmt = s
+		}
 	}
 
+	return stmt
+}
+
+// synchronize skips the rest of a statement that failed to parse, leaving
+// currToken on the first token of the next statement at the given brace
+// depth, or on the brace that closes the enclosing block.
+func (p *Parser) synchronize(depth int) {
+	p.panicking = false
+
+	for !p.currTokenIs(token.EOF) {
+		if p.depth < depth {
+			return
+		}
+
+		if p.depth == depth {
+			if p.currTokenIs(token.SEMICOLON) {
+				p.nextToken()
+				return
+			}
+
+			switch p.peekToken.Type {
+			case token.LET, token.CONST, token.RETURN, token.WHILE, token.FOR:
+				p.nextToken()
+				return
+			}
+		}
+
+		p.nextToken()
+	}
 }
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
@@ -144,15 +263,57 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		return nil
 	}
 
+	stmt.Value = p.parseBindingValue(stmt.Name)
+
+	return stmt
+}
+
+func (p *Parser) parseConstStatement() *ast.ConstStatement {
+	stmt := &ast.ConstStatement{Token: p.currToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+
+	if !p.peekTokenIs(token.ASSIGN) {
+		p.addDiagnostic(Diagnostic{
+			Severity: SeverityError,
+			Code:     CodeMissingInit,
+			Message:  fmt.Sprintf("missing initializer in const declaration of %s", stmt.Name),
+			Pos:      p.peekToken.Pos,
+			End:      p.peekToken.End,
+			Expected: []token.TokenType{token.ASSIGN},
+			Found:    p.peekToken.Type,
+			Hint:     "a const must be given its value where it is declared",
+		})
+		return nil
+	}
+
 	p.nextToken()
 
-	stmt.Value = p.parseExpression(LOWEST)
+	stmt.Value = p.parseBindingValue(stmt.Name)
+
+	return stmt
+}
+
+// parseBindingValue parses the value of a let or const statement, with
+// currToken on the = sign.
+func (p *Parser) parseBindingValue(name *ast.Identifier) ast.Expression {
+	p.nextToken()
+
+	value := p.parseExpression(LOWEST)
+
+	if fl, ok := value.(*ast.FunctionLiteral); ok {
+		fl.Name = name.Value
+	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
-	return stmt
+	return value
 }
 
 func (p *Parser) currTokenIs(t token.TokenType) bool {
@@ -173,15 +334,51 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 	}
 }
 
+// Errors returns every error diagnostic in its string form.
 func (p *Parser) Errors() []string {
-	return p.errors
+	errors := []string{}
+
+	for _, d := range p.diagnostics {
+		if d.Severity == SeverityError {
+			errors = append(errors, d.String())
+		}
+	}
+
+	return errors
+}
+
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+func (p *Parser) addDiagnostic(d Diagnostic) {
+	if d.Severity == SeverityError {
+		if p.panicking {
+			return
+		}
+		p.panicking = true
+	}
+
+	p.diagnostics = append(p.diagnostics, d)
 }
 
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
-		t, p.peekToken.Type)
+	d := Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeUnexpectedToken,
+		Message: fmt.Sprintf("expected next token to be %s, got %s instead",
+			t, p.peekToken.Type),
+		Pos:      p.peekToken.Pos,
+		End:      p.peekToken.End,
+		Expected: []token.TokenType{t},
+		Found:    p.peekToken.Type,
+	}
 
-	p.errors = append(p.errors, msg)
+	if p.peekTokenIs(token.EOF) {
+		d.Hint = "the input ended early, check for a missing closing bracket"
+	}
+
+	p.addDiagnostic(d)
 }
 
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
@@ -252,9 +449,41 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(p.currToken.Literal, 0, 64)
 
+	if err == nil {
+		literal.Value = value
+		return literal
+	}
+
+	if n, ok := new(big.Int).SetString(p.currToken.Literal, 0); ok {
+		literal.Big = n
+		return literal
+	}
+
+	p.addDiagnostic(Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeInvalidInteger,
+		Message:  fmt.Sprintf("could not parse %q as integer", p.currToken.Literal),
+		Pos:      p.currToken.Pos,
+		End:      p.currToken.End,
+		Found:    p.currToken.Type,
+	})
+	return nil
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	literal := &ast.FloatLiteral{Token: p.currToken}
+
+	value, err := strconv.ParseFloat(p.currToken.Literal, 64)
+
 	if err != nil {
-		msg := fmt.Sprintf("Could not parse %q as integer", p.currToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addDiagnostic(Diagnostic{
+			Severity: SeverityError,
+			Code:     CodeInvalidFloat,
+			Message:  fmt.Sprintf("could not parse %q as float", p.currToken.Literal),
+			Pos:      p.currToken.Pos,
+			End:      p.currToken.End,
+			Found:    p.currToken.Type,
+		})
 		return nil
 	}
 
@@ -263,8 +492,47 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	d := Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeNoPrefixParseFn,
+		Message:  fmt.Sprintf("no prefix parse function for %s found", t),
+		Pos:      p.currToken.Pos,
+		End:      p.currToken.End,
+		Found:    t,
+	}
+
+	if t == token.EOF {
+		d.Hint = "the input ended where an expression was expected"
+	}
+
+	p.addDiagnostic(d)
+}
+
+// parseIllegal reports a token the lexer could not make sense of.
+func (p *Parser) parseIllegal() ast.Expression {
+	d := Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeIllegalToken,
+		Message:  fmt.Sprintf("illegal character %q", p.currToken.Literal),
+		Pos:      p.currToken.Pos,
+		End:      p.currToken.End,
+		Found:    p.currToken.Type,
+	}
+
+	switch {
+	case strings.HasPrefix(p.currToken.Literal, "/*"):
+		d.Message = "unterminated block comment"
+		d.Hint = "close the comment with */"
+	case strings.HasPrefix(p.currToken.Literal, `"`):
+		d.Message = "unterminated string literal"
+		d.Hint = `close the string with ", or write \" for a quote inside it`
+	case strings.HasPrefix(p.currToken.Literal, "`"):
+		d.Message = "unterminated raw string literal"
+		d.Hint = "close the string with `"
+	}
+
+	p.addDiagnostic(d)
+	return nil
 }
 
 func (p *Parser) parsePrefixExpression() ast.Expression {
@@ -286,11 +554,56 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.currPrecendece()
+
+	// ** is right-associative, so 2 ** 3 ** 2 is 2 ** (3 ** 2)
+	if p.currTokenIs(token.POWER) {
+		precedence--
+	}
+
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 	return expression
 }
 
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	expression := &ast.AssignExpression{
+		Token:    p.currToken,
+		Target:   target,
+		Operator: p.currToken.Literal,
+	}
+
+	valid := p.checkAssignTarget(target)
+
+	// assignment is right-associative, so a = b = 1 is a = (b = 1)
+	p.nextToken()
+	expression.Value = p.parseExpression(ASSIGN - 1)
+
+	if !valid {
+		return nil
+	}
+	return expression
+}
+
+func (p *Parser) checkAssignTarget(target ast.Expression) bool {
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+		return true
+	case nil:
+		return false // already reported
+	}
+
+	p.addDiagnostic(Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeInvalidAssign,
+		Message:  fmt.Sprintf("cannot assign to %s", target.String()),
+		Pos:      target.Pos(),
+		End:      p.currToken.Pos,
+		Found:    p.currToken.Type,
+		Hint:     "only a variable or an index expression can be assigned to",
+	})
+	return false
+}
+
 func (p *Parser) peekPrecedence() int {
 	if p, ok := precedences[p.peekToken.Type]; ok {
 		return p
@@ -346,6 +659,25 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
 
+		// else if (...) { ... } is sugar for else { if (...) { ... } }
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+
+			alternative := &ast.BlockStatement{Token: p.currToken}
+			nested := p.parseIfExpression()
+
+			if nested == nil {
+				return nil
+			}
+
+			alternative.Statements = []ast.Statement{
+				&ast.ExpressionStatement{Token: alternative.Token, Expression: nested},
+			}
+			expression.Alternative = alternative
+
+			return expression
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -356,14 +688,88 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.currToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	stmt := &ast.ForStatement{Token: p.currToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Variable = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.currToken}
+	depth := p.depth
 
 	p.nextToken()
 
 	for !p.currTokenIs(token.RBRACE) && !p.currTokenIs(token.EOF) {
 		stmt := p.parseStatement()
 
+		if p.panicking {
+			p.synchronize(depth)
+
+			if p.depth < depth {
+				break
+			}
+			continue
+		}
+
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
@@ -381,7 +787,9 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	if !p.parseFunctionParameters(lit) {
+		return nil
+	}
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
@@ -392,32 +800,67 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// parseFunctionParameters parses `(a, b = 10, ...rest)` into the
+// parameters, defaults and rest parameter of lit.
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) bool {
+	lit.Parameters = []*ast.Identifier{}
+	lit.Defaults = []ast.Expression{}
 
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return true
 	}
 
-	p.nextToken()
+	for {
+		if p.peekTokenIs(token.ELLIPSIS) {
+			p.nextToken()
 
-	ident := &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
-	identifiers = append(identifiers, ident)
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
 
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
-		p.nextToken()
+			// the rest parameter has to be the last one
+			lit.Rest = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+			break
+		}
+
+		if !p.expectPeek(token.IDENT) {
+			return false
+		}
 
 		ident := &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
-		identifiers = append(identifiers, ident)
-	}
 
-	if !p.expectPeek(token.RPAREN) {
-		return nil
+		var value ast.Expression
+
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parseExpression(LOWEST)
+		} else if len(lit.Defaults) > 0 && lit.Defaults[len(lit.Defaults)-1] != nil {
+			p.addDiagnostic(Diagnostic{
+				Severity: SeverityError,
+				Code:     CodeInvalidParams,
+				Message: fmt.Sprintf("parameter %s without a default follows a parameter with one",
+					ident.Value),
+				Pos:   ident.Token.Pos,
+				End:   ident.Token.End,
+				Found: ident.Token.Type,
+				Hint:  "move required parameters before the ones with defaults",
+			})
+			return false
+		}
+
+		lit.Parameters = append(lit.Parameters, ident)
+		lit.Defaults = append(lit.Defaults, value)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+
+		p.nextToken()
 	}
 
-	return identifiers
+	return p.expectPeek(token.RPAREN)
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
@@ -430,6 +873,37 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.currToken, Value: p.currToken.Literal}
 }
 
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{
+		Token:   p.currToken,
+		Strings: []string{p.currToken.Literal},
+	}
+
+	for {
+		p.nextToken()
+		str.Expressions = append(str.Expressions, p.parseExpression(LOWEST))
+
+		if p.peekTokenIs(token.TEMPLATE_MIDDLE) {
+			p.nextToken()
+			str.Strings = append(str.Strings, p.currToken.Literal)
+			continue
+		}
+
+		// the rest of a string that is never closed
+		if p.peekTokenIs(token.ILLEGAL) {
+			p.nextToken()
+			return p.parseIllegal()
+		}
+
+		if !p.expectPeek(token.TEMPLATE_TAIL) {
+			return nil
+		}
+
+		str.Strings = append(str.Strings, p.currToken.Literal)
+		return str
+	}
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.currToken}
 
@@ -465,9 +939,17 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp := &ast.IndexExpression{Token: p.currToken, Left: left}
 
+	if p.peekTokenIs(token.COLON) {
+		return p.parseSliceExpression(exp)
+	}
+
 	p.nextToken()
 	exp.Index = p.parseExpression(LOWEST)
 
+	if p.peekTokenIs(token.COLON) {
+		return p.parseSliceExpression(exp)
+	}
+
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
@@ -475,6 +957,37 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+// parseSliceExpression continues an index expression at the : that makes
+// it a slice. The index, if any, is the start of the slice.
+func (p *Parser) parseSliceExpression(index *ast.IndexExpression) ast.Expression {
+	exp := &ast.SliceExpression{Token: index.Token, Left: index.Left, Start: index.Index}
+
+	p.nextToken()
+	exp.End = p.parseSliceBound()
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		exp.Step = p.parseSliceBound()
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return exp
+}
+
+// parseSliceBound parses the bound after the : in currToken, returning nil
+// if it is omitted.
+func (p *Parser) parseSliceBound() ast.Expression {
+	if p.peekTokenIs(token.COLON) || p.peekTokenIs(token.RBRACKET) {
+		return nil
+	}
+
+	p.nextToken()
+	return p.parseExpression(LOWEST)
+}
+
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.currToken}
 	hash.Pairs = make(map[ast.Expression]ast.Expression)
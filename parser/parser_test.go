@@ -3,6 +3,7 @@ package parser
 import (
 	"Mon/ast"
 	"Mon/lexer"
+	"Mon/token"
 	"fmt"
 	"testing"
 )
@@ -44,6 +45,55 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatement(t *testing.T) {
+	input := `const max = fn(a, b) { if (a > b) { a } else { b } };`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statments does not contain %d, got=%d",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ConstStatement)
+
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ConstStatement, got=%T",
+			program.Statements[0])
+	}
+
+	if stmt.Name.Value != "max" {
+		t.Errorf("stmt.Name.Value is not %q, got=%q", "max", stmt.Name.Value)
+	}
+
+	fn, ok := stmt.Value.(*ast.FunctionLiteral)
+
+	if !ok {
+		t.Fatalf("stmt.Value is not ast.FunctionLiteral, got=%T", stmt.Value)
+	}
+
+	if fn.Name != "max" {
+		t.Errorf("function is not named %q, got=%q", "max", fn.Name)
+	}
+}
+
+func TestConstStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x;", "1:8: missing initializer in const declaration of x"},
+		{"const x", "1:8: missing initializer in const declaration of x"},
+		{"const = 1;", "1:7: expected next token to be IDENT, got = instead"},
+	}
+
+	for _, tt := range tests {
+		testParserError(t, tt.input, tt.expected)
+	}
+}
+
 func TestReturnStatement(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -152,6 +202,48 @@ func TestIntegerLiteral(t *testing.T) {
 	}
 }
 
+func TestFloatLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"0.5", 0.5},
+		{"1e3", 1000},
+		{"2.5e-1", 0.25},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statments, got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got=%T",
+				program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+
+		if !ok {
+			t.Fatalf("exp is not ast.FloatLiteral, got=%T", stmt.Expression)
+		}
+
+		if literal.Value != tt.expected {
+			t.Errorf("literal.Value is not %g, got=%g", tt.expected, literal.Value)
+		}
+	}
+}
+
 func TestParsingPrefixExpressins(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -160,6 +252,7 @@ func TestParsingPrefixExpressins(t *testing.T) {
 	}{
 		{"!5;", "!", 5},
 		{"-15;", "-", 15},
+		{"~15;", "~", 15},
 		{"!true;", "!", true},
 		{"!false;", "!", false},
 	}
@@ -222,6 +315,17 @@ func TestParsingInfixExpression(t *testing.T) {
 		{"true == true;", true, "==", true},
 		{"true != false;", true, "!=", false},
 		{"false == false;", false, "==", false},
+		{"true && false;", true, "&&", false},
+		{"a || b;", "a", "||", "b"},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
 	}
 
 	for _, tt := range infixTests {
@@ -355,6 +459,58 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a + b + c * d / f + g)",
 			"add((((a + b) + ((c * d) / f)) + g))",
 		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a && b || c && d",
+			"((a && b) || (c && d))",
+		},
+		{
+			"a == b && c < d || !e",
+			"(((a == b) && (c < d)) || (!e))",
+		},
+		{
+			"a <= b == c >= d",
+			"((a <= b) == (c >= d))",
+		},
+		{
+			"a + b % c",
+			"(a + (b % c))",
+		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"-a ** b * c",
+			"((-(a ** b)) * c)",
+		},
+		{
+			"a * b ** -c",
+			"(a * (b ** (-c)))",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)))",
+		},
+		{
+			"a & b == c",
+			"(a & (b == c))",
+		},
+		{
+			"a << b + c < d",
+			"((a << (b + c)) < d)",
+		},
+		{
+			"a || b | c",
+			"(a || (b | c))",
+		},
+		{
+			"~a & b",
+			"((~a) & b)",
+		},
 		{
 			"a * [1, 2, 3, 4][b * c] * d",
 			"((a * ([1, 2, 3, 4][(b * c)])) * d)",
@@ -363,6 +519,22 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"x = y = 1 + 2",
+			"(x = (y = (1 + 2)))",
+		},
+		{
+			"x += a || b",
+			"(x += (a || b))",
+		},
+		{
+			"a[1:n - 1][0]",
+			"((a[1:(n - 1)])[0])",
+		},
+		{
+			"a[0] = b[i + 1] += 2",
+			"((a[0]) = ((b[(i + 1)]) += 2))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -547,6 +719,190 @@ func TestIfElseExpression(t *testing.T) {
 
 }
 
+func TestElseIfExpression(t *testing.T) {
+	input := `if (x < y) { x } else if (x > y) { y } else { 0 }`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression, got=%T", stmt.Expression)
+	}
+
+	if len(exp.Alternative.Statements) != 1 {
+		t.Fatalf("alternative is not 1 statement, got=%d",
+			len(exp.Alternative.Statements))
+	}
+
+	alternative := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+	nested, ok := alternative.Expression.(*ast.IfExpression)
+
+	if !ok {
+		t.Fatalf("alternative is not ast.IfExpression, got=%T", alternative.Expression)
+	}
+
+	if !testInfixExpression(t, nested.Condition, "x", ">", "y") {
+		return
+	}
+
+	if nested.Alternative == nil || nested.Alternative.String() != "0" {
+		t.Errorf("nested alternative is not %q, got=%v", "0", nested.Alternative)
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 => a, -2.5 => b, _ => c }", "match x { 1 => a, (-2.5) => b, _ => c }"},
+		{`match (x) { "a" => 1, true => 2, }`, "match x { a => 1, true => 2 }"},
+		{"match (xs) { [] => 0, [a, [b, _], ...rest] => a + b }",
+			"match xs { [] => 0, [a, [b, _], ...rest] => (a + b) }"},
+		{`match (h) { {"x": 0, "y": y} => y, {} => 1 }`, "match h { {x:0, y:y} => y, {} => 1 }"},
+		{"match (f(x)) { n => n * 2 }", "match f(x) { n => (n * 2) }"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+		if _, ok := stmt.Expression.(*ast.MatchExpression); !ok {
+			t.Fatalf("stmt.Expression is not ast.MatchExpression, got=%T", stmt.Expression)
+		}
+
+		if stmt.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 + 2 => 3 }", "1:15: expected next token to be =>, got + instead"},
+		{"match (x) { f() => 3 }", "1:14: expected next token to be =>, got ( instead"},
+		{"match (x) { -y => 3 }", "1:13: invalid pattern: -"},
+		{"match (x) { [...] => 3 }", "1:17: expected next token to be IDENT, got ] instead"},
+		{"match (x) { [...a, b] => 3 }", "1:18: expected next token to be ], got , instead"},
+		{"match (x) { {a: 1} => 3 }", "1:14: invalid pattern: a"},
+		{"match (x) { 1 => 2 3 => 4 }", "1:20: expected next token to be ,, got INT instead"},
+		{"match x { }", "1:7: expected next token to be (, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		testParserError(t, tt.input, tt.expected)
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	input := `while (x < y) { x }`
+
+	l := lexer.New(input)
+	p := New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements, got=%d",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.WhileStatement, got=%T",
+			program.Statements[0])
+	}
+
+	if !testInfixExpression(t, stmt.Condition, "x", "<", "y") {
+		return
+	}
+
+	if len(stmt.Body.Statements) != 1 {
+		t.Fatalf("body is not 1 statement, got=%d", len(stmt.Body.Statements))
+	}
+
+	body, ok := stmt.Body.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("body statement is not ast.ExpressionStatement, got=%T",
+			stmt.Body.Statements[0])
+	}
+
+	testIdentifier(t, body.Expression, "x")
+}
+
+func TestForStatement(t *testing.T) {
+	input := `for (item in items) { puts(item); }; 5`
+
+	l := lexer.New(input)
+	p := New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain %d statements, got=%d",
+			2, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ForStatement)
+
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ForStatement, got=%T",
+			program.Statements[0])
+	}
+
+	testIdentifier(t, stmt.Variable, "item")
+	testIdentifier(t, stmt.Iterable, "items")
+
+	if len(stmt.Body.Statements) != 1 {
+		t.Fatalf("body is not 1 statement, got=%d", len(stmt.Body.Statements))
+	}
+
+	if stmt.Body.String() != "puts(item)" {
+		t.Errorf("body is not %q, got=%q", "puts(item)", stmt.Body.String())
+	}
+}
+
+func TestForStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x of y) { }", "1:8: expected next token to be IN, got IDENT instead"},
+		{"for (1 in y) { }", "1:6: expected next token to be IDENT, got INT instead"},
+		{"while x { }", "1:7: expected next token to be (, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+
+		if len(errors) != 1 {
+			t.Errorf("expected 1 error for %q, got=%q", tt.input, errors)
+			continue
+		}
+
+		if errors[0] != tt.expected {
+			t.Errorf("wrong error. want=%q, got=%q", tt.expected, errors[0])
+		}
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := `fn(x, y) { x + y }`
 
@@ -630,6 +986,138 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestFunctionDefaultAndRestParameterParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedParams   []string
+		expectedDefaults []interface{}
+		expectedRest     string
+	}{
+		{"fn(a, b = 10) { }", []string{"a", "b"}, []interface{}{nil, 10}, ""},
+		{"fn(a = x, b = true) { }", []string{"a", "b"}, []interface{}{"x", true}, ""},
+		{"fn(...rest) { }", []string{}, []interface{}{}, "rest"},
+		{"fn(a, b = 1, ...rest) { }", []string{"a", "b"}, []interface{}{nil, 1}, "rest"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmt.Expression.(*ast.FunctionLiteral)
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Fatalf("length parameter wrong, want %d, got=%d",
+				len(tt.expectedParams), len(function.Parameters))
+		}
+
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+
+			if tt.expectedDefaults[i] == nil {
+				if function.Defaults[i] != nil {
+					t.Errorf("parameter %s has a default, got=%s",
+						ident, function.Defaults[i])
+				}
+				continue
+			}
+
+			testLiteralExpression(t, function.Defaults[i], tt.expectedDefaults[i])
+		}
+
+		if tt.expectedRest == "" {
+			if function.Rest != nil {
+				t.Errorf("function.Rest is not nil, got=%s", function.Rest)
+			}
+			continue
+		}
+
+		testLiteralExpression(t, function.Rest, tt.expectedRest)
+	}
+}
+
+func TestFunctionParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(a = 1, b) { }", "1:11: parameter b without a default follows a parameter with one"},
+		{"fn(...rest, a) { }", "1:11: expected next token to be ), got , instead"},
+		{"fn(...) { }", "1:7: expected next token to be IDENT, got ) instead"},
+	}
+
+	for _, tt := range tests {
+		testParserError(t, tt.input, tt.expected)
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedTarget   string
+		expectedOperator string
+		expectedValue    interface{}
+	}{
+		{"x = 5;", "x", "=", 5},
+		{"y += 1", "y", "+=", 1},
+		{"total -= x", "total", "-=", "x"},
+		{"z *= true", "z", "*=", true},
+		{"z /= 2", "z", "/=", 2},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.AssignExpression)
+
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.AssignExpression, got=%T", stmt.Expression)
+		}
+
+		if !testIdentifier(t, exp.Target, tt.expectedTarget) {
+			return
+		}
+
+		if exp.Operator != tt.expectedOperator {
+			t.Errorf("exp.Operator is not %q, got=%q", tt.expectedOperator, exp.Operator)
+		}
+
+		testLiteralExpression(t, exp.Value, tt.expectedValue)
+	}
+}
+
+func TestAssignExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 = 2", "1:1: cannot assign to 1"},
+		{"f() += 2", "1:2: cannot assign to f()"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+
+		if len(errors) != 1 {
+			t.Errorf("expected 1 error for %q, got=%q", tt.input, errors)
+			continue
+		}
+
+		if errors[0] != tt.expected {
+			t.Errorf("wrong error. want=%q, got=%q", tt.expected, errors[0])
+		}
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	input := `add(1, 2 * 3, 4 + 5)`
 
@@ -693,6 +1181,60 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestInterpolatedString(t *testing.T) {
+	input := `"hello ${name}, you are ${age + 1}!"`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	str, ok := stmt.Expression.(*ast.InterpolatedString)
+
+	if !ok {
+		t.Fatalf("exp not *ast.InterpolatedString, got=%T", stmt.Expression)
+	}
+
+	expectedStrings := []string{"hello ", ", you are ", "!"}
+
+	if len(str.Strings) != len(expectedStrings) {
+		t.Fatalf("wrong number of strings. want=%d, got=%d",
+			len(expectedStrings), len(str.Strings))
+	}
+
+	for i, s := range expectedStrings {
+		if str.Strings[i] != s {
+			t.Errorf("str.Strings[%d] is not %q, got=%q", i, s, str.Strings[i])
+		}
+	}
+
+	if len(str.Expressions) != 2 {
+		t.Fatalf("wrong number of expressions. want=2, got=%d", len(str.Expressions))
+	}
+
+	testIdentifier(t, str.Expressions[0], "name")
+	testInfixExpression(t, str.Expressions[1], "age", "+", 1)
+
+	if str.String() != "hello ${name}, you are ${(age + 1)}!" {
+		t.Errorf("str.String() wrong, got=%q", str.String())
+	}
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${x y} b"`, "1:8: expected next token to be TEMPLATE_TAIL, got IDENT instead"},
+		{`"a ${} b"`, "1:6: no prefix parse function for TEMPLATE_TAIL found"},
+		{`"a ${x} b`, "1:7: unterminated string literal"},
+	}
+
+	for _, tt := range tests {
+		testParserError(t, tt.input, tt.expected)
+	}
+}
+
 func TestArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
@@ -764,6 +1306,70 @@ func TestParsingIndexExpression(t *testing.T) {
 	}
 }
 
+func TestParsingSliceExpression(t *testing.T) {
+	input := "myArray[1:2 + 3]"
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.SliceExpression)
+
+	if !ok {
+		t.Fatalf("exp not *ast.SliceExpression, got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Left, "myArray") {
+		return
+	}
+
+	if !testLiteralExpression(t, exp.Start, 1) {
+		return
+	}
+
+	testInfixExpression(t, exp.End, 2, "+", 3)
+}
+
+func TestParsingSliceBounds(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[1:2]", "(a[1:2])"},
+		{"a[1:]", "(a[1:])"},
+		{"a[:2]", "(a[:2])"},
+		{"a[:]", "(a[:])"},
+		{"a[::]", "(a[:])"},
+		{"a[::-1]", "(a[::(-1)])"},
+		{"a[1:-1:2]", "(a[1:(-1):2])"},
+		{"a[i + 1::n * 2]", "(a[(i + 1)::(n * 2)])"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if _, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.SliceExpression); !ok {
+			t.Errorf("%q is not parsed as a slice", tt.input)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	for _, input := range []string{"a[1:2:3:4]", "a[1 2]", "a[:1"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected errors for %q, got none", input)
+		}
+	}
+}
+
 func TestParsingHashLiteralEmpty(t *testing.T) {
 	input := `{}`
 
@@ -947,6 +1553,192 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 
 }
 
+func TestParserErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x 5;", "test.mon:1:7: expected next token to be =, got INT instead"},
+		{"let x = 5;\n  let = 10;", "test.mon:2:7: expected next token to be IDENT, got = instead"},
+		{"\n\n   ;", "test.mon:3:4: no prefix parse function for ; found"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.NewFile("test.mon", tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expected, errors[0])
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// leading
+let x = /* inline */ 5; // trailing
+/* a
+   block */
+x`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if program.String() != "let x = 5;x" {
+		t.Errorf("program is not %q, got=%q", "let x = 5;x", program.String())
+	}
+}
+
+func TestIllegalTokens(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1 /* not closed", "1:11: unterminated block comment"},
+		{"let x = #;", `1:9: illegal character "#"`},
+		{`let s = "abc;`, "1:9: unterminated string literal"},
+		{"let s = `abc;", "1:9: unterminated raw string literal"},
+	}
+
+	for _, tt := range tests {
+		testParserError(t, tt.input, tt.expected)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	p := New(lexer.NewFile("test.mon", "let x 5;"))
+	p.ParseProgram()
+
+	diagnostics := p.Diagnostics()
+
+	if len(diagnostics) == 0 {
+		t.Fatalf("expected diagnostics, got none")
+	}
+
+	d := diagnostics[0]
+
+	if d.Severity != SeverityError {
+		t.Errorf("d.Severity is not %s, got=%s", SeverityError, d.Severity)
+	}
+
+	if d.Code != CodeUnexpectedToken {
+		t.Errorf("d.Code is not %s, got=%s", CodeUnexpectedToken, d.Code)
+	}
+
+	if d.Message != "expected next token to be =, got INT instead" {
+		t.Errorf("d.Message wrong, got=%q", d.Message)
+	}
+
+	if d.Pos.Line != 1 || d.Pos.Column != 7 || d.End.Column != 8 {
+		t.Errorf("d has wrong span, got=%s-%s", d.Pos, d.End)
+	}
+
+	if len(d.Expected) != 1 || d.Expected[0] != token.ASSIGN {
+		t.Errorf("d.Expected is not [%s], got=%v", token.ASSIGN, d.Expected)
+	}
+
+	if d.Found != token.INT {
+		t.Errorf("d.Found is not %s, got=%s", token.INT, d.Found)
+	}
+
+	if p.Errors()[0] != d.String() {
+		t.Errorf("Errors() does not match diagnostic, got=%q want=%q",
+			p.Errors()[0], d.String())
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedErrors     []string
+		expectedStatements int
+	}{
+		{
+			"let x 5;\nlet y = 10;\nlet = 3;\nlet z = fn(a) { a + };\nz(y);",
+			[]string{
+				"1:7: expected next token to be =, got INT instead",
+				"3:5: expected next token to be IDENT, got = instead",
+				"4:21: no prefix parse function for } found",
+			},
+			3,
+		},
+		{
+			"if (x { 1 }\nlet a = 1;",
+			[]string{"1:7: expected next token to be ), got { instead"},
+			1,
+		},
+		{
+			`fn() { let h = {"a" 1}; h }; 5`,
+			[]string{"1:21: expected next token to be :, got INT instead"},
+			2,
+		},
+		{
+			"let f = fn(x) { let = 1; x }; f(1, )\nlet b = 2;",
+			[]string{
+				"1:21: expected next token to be IDENT, got = instead",
+				"1:36: no prefix parse function for ) found",
+			},
+			2,
+		},
+		{
+			"let x 5\nwhile (true) { let = 1 }\nlet z = 2;",
+			[]string{
+				"1:7: expected next token to be =, got INT instead",
+				"2:20: expected next token to be IDENT, got = instead",
+			},
+			2,
+		},
+		{
+			"let x 5\nfor (i in [1]) { let = 1 }",
+			[]string{
+				"1:7: expected next token to be =, got INT instead",
+				"2:22: expected next token to be IDENT, got = instead",
+			},
+			1,
+		},
+		{
+			"let x 5\nconst y;\nconst z = 1;",
+			[]string{
+				"1:7: expected next token to be =, got INT instead",
+				"2:8: missing initializer in const declaration of y",
+			},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+
+		errors := p.Errors()
+
+		if len(errors) != len(tt.expectedErrors) {
+			t.Errorf("wrong number of errors for %q. want=%d, got=%d (%q)",
+				tt.input, len(tt.expectedErrors), len(errors), errors)
+			continue
+		}
+
+		for i, msg := range tt.expectedErrors {
+			if errors[i] != msg {
+				t.Errorf("errors[%d] wrong. want=%q, got=%q", i, msg, errors[i])
+			}
+		}
+
+		if len(program.Statements) != tt.expectedStatements {
+			t.Errorf("program.Statements does not contain %d statements, got=%d (%s)",
+				tt.expectedStatements, len(program.Statements), program.String())
+		}
+	}
+}
+
 func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 
 	if literal := s.TokenLiteral(); literal != "let" {
@@ -977,7 +1769,7 @@ func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 }
 
 func checkParserErrors(t *testing.T, p *Parser) {
-	errors := p.errors
+	errors := p.Errors()
 
 	if len(errors) == 0 {
 		return
@@ -991,6 +1783,24 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	t.FailNow()
 }
 
+// testParserError checks that parsing input reports exactly one error,
+// with the expected message.
+func testParserError(t *testing.T, input string, expected string) {
+	p := New(lexer.New(input))
+	p.ParseProgram()
+
+	errors := p.Errors()
+
+	if len(errors) != 1 {
+		t.Errorf("expected 1 error for %q, got=%q", input, errors)
+		return
+	}
+
+	if errors[0] != expected {
+		t.Errorf("wrong error. want=%q, got=%q", expected, errors[0])
+	}
+}
+
 func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
 
 	integ, ok := il.(*ast.IntegerLiteral)
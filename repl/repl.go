@@ -44,7 +44,7 @@ func Start(in io.Reader, out io.Writer) {
 		program := p.ParseProgram()
 
 		if len(p.Errors()) != 0 {
-			printParseErrors(out, p.Errors())
+			printParseErrors(out, p.Diagnostics())
 			continue
 		}
 
@@ -55,14 +55,21 @@ func Start(in io.Reader, out io.Writer) {
 			io.WriteString(out, "\n")
 		}
 
+		if errObj, ok := evaluated.(*object.Error); ok {
+			io.WriteString(out, errObj.StackTrace())
+		}
+
 	}
 }
 
-func printParseErrors(out io.Writer, errors []string) {
+func printParseErrors(out io.Writer, diagnostics []parser.Diagnostic) {
 	io.WriteString(out, MONKEY_FACE)
 	io.WriteString(out, "Woops! we ran into some monkey business here!\n")
 	io.WriteString(out, "parser errors:\n")
-	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
+	for _, d := range diagnostics {
+		io.WriteString(out, "\t"+d.String()+"\n")
+		if d.Hint != "" {
+			io.WriteString(out, "\t\thint: "+d.Hint+"\n")
+		}
 	}
 }
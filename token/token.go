@@ -1,10 +1,33 @@
 package token
 
+import "fmt"
+
 type TokenType string
 
+// Position is a location in the source. Line and Column start at 1; the zero
+// value means the position is unknown.
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position // first character of the token
+	End     Position // just past the last character of the token
+
+	// Comments holds the comments between the previous token and this one,
+	// if the lexer was asked to keep them.
+	Comments []Comment
+}
+
+// Comment is a // or /* */ comment, including its markers.
+type Comment struct {
+	Text string
+	Pos  Position
+	End  Position
 }
 
 const (
@@ -14,8 +37,15 @@ const (
 	// identifier + literal
 	IDENT  = "IDENT"
 	INT    = "INT"
+	FLOAT  = "FLOAT"
 	STRING = "STRING"
 
+	// a string with ${...} interpolations is split into a head, up to the
+	// first interpolation, middles between them, and a tail after the last
+	TEMPLATE_HEAD   = "TEMPLATE_HEAD"
+	TEMPLATE_MIDDLE = "TEMPLATE_MIDDLE"
+	TEMPLATE_TAIL   = "TEMPLATE_TAIL"
+
 	// operators
 	ASSIGN   = "="
 	PLUS     = "+"
@@ -23,13 +53,32 @@ const (
 	BANG     = "!"
 	ASTERISK = "*"
 	SLASH    = "/"
+	PERCENT  = "%"
+	POWER    = "**"
+
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+
+	AMPERSAND = "&"
+	PIPE      = "|"
+	CARET     = "^"
+	TILDE     = "~"
+	LSHIFT    = "<<"
+	RSHIFT    = ">>"
 
-	GT = ">"
-	LT = "<"
+	GT    = ">"
+	LT    = "<"
+	GT_EQ = ">="
+	LT_EQ = "<="
 
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	AND = "&&"
+	OR  = "||"
+
 	// delimeters
 	LPAREN = "("
 	RPAREN = ")"
@@ -43,25 +92,37 @@ const (
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	ELLIPSIS  = "..."
+	FAT_ARROW = "=>"
 
 	// keywords
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
+	MATCH    = "MATCH"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
 )
 
 var keywords = map[string]TokenType{
 	"fn":     FUNCTION,
 	"let":    LET,
+	"const":  CONST,
+	"match":  MATCH,
 	"if":     IF,
 	"else":   ELSE,
 	"return": RETURN,
 	"true":   TRUE,
 	"false":  FALSE,
+	"while":  WHILE,
+	"for":    FOR,
+	"in":     IN,
 }
 
 func LookupIdent(ident string) TokenType {
@@ -70,3 +131,22 @@ func LookupIdent(ident string) TokenType {
 	}
 	return IDENT
 }
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		if p.Filename != "" {
+			return p.Filename
+		}
+		return "-"
+	}
+
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}